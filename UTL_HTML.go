@@ -48,6 +48,12 @@
 //  - func (p_HTML *T_HTML) NL() *T_HTML // NewLine
 //  - func (p_HTML *T_HTML) Write(w http.ResponseWriter)
 //  - func (p_HTML *T_HTML) CloseTagsAndWrite(w http.ResponseWriter)
+//  - func (p_HTML *T_HTML) FlushEvery(w http.ResponseWriter, p_Rows int, p_Interval time.Duration) *T_HTML
+//  - func (p_HTML *T_HTML) Flush() *T_HTML
+//  - func (p_HTML *T_HTML) WithContext(ctx context.Context) *T_HTML
+//  - func (p_HTML *T_HTML) Cancelled() bool
+//
+// Long tables don't have to be buffered completely: With FlushEvery() the table functions write the generated content to the browser every n rows and/or every time-interval, the tag-stack is not affected, so the closing tags are generated as usual. With WithContext(r.Context()) the table functions stop generating rows when the client disconnects. They stop as well when writing to w fails, the error is returned by Err().
 //
 //
 // # Functions for generic tags
@@ -166,26 +172,192 @@
 //
 // # Forms [file: UTL_HTML_Form]
 //
-// 🚧 Currently under construction, TODO: More functions for other input-types, maybe standard form-structures, … 🚧
-//  - func (p_HTML *T_HTML) FormOpen(p_action, p_method string, p_Attributes ...string) *T_HTML
+// The form controls of HTML5, each as method appending to the document and as function returning a string. They share the attribute handling of TextField(): empty parameters are left out and p_Attributes are appended; the methods show the errors of FormErrors(). Values are inserted as they are. RadioGroup() and DataList() take their items from a map - the keys are the labels - from a slice of values or from the other sources of Select(). Errors of a *sql.Rows are reported by Err() of the methods, the functions panic.
+//  - func (p_HTML *T_HTML) FormOpen(p_action, p_method string, p_Attributes ...string) *T_HTML, POST forms get the CSRF token, see SetCSRF
 //  - func (p_HTML *T_HTML) BoolField(p_name string, p_checked bool, p_Attributes ...string) *T_HTML
 //  - func (p_HTML *T_HTML) HiddenField(p_name, p_value string, p_Attributes... string ) *T_HTML
 //  - func (p_HTML *T_HTML) SubmitButton(p_name, p_label, p_value, p_Attributes ...string) *T_HTML
-//  - func (p_HTML *T_HTML) TextField(p_name, p_size, p_maxlen, p_value, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) TextField(p_name, p_size, p_maxlength, p_value, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) EmailField(p_name, p_size, p_maxlength, p_value, p_Attributes ...string) *T_HTML, URLField, TelField, PasswordField, SearchField
+//  - func (p_HTML *T_HTML) NumberField(p_name, p_min, p_max, p_step, p_value string, p_Attributes ...string) *T_HTML, RangeField
+//  - func (p_HTML *T_HTML) DateField(p_name, p_min, p_max, p_value string, p_Attributes ...string) *T_HTML, DateTimeField, TimeField
+//  - func (p_HTML *T_HTML) ColorField(p_name, p_value string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) InputField(p_Type, p_name, p_value string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) FileField(p_name, p_accept string, p_multiple bool, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) TextArea(p_name, p_rows, p_cols, p_value string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) RadioGroup(p_name, p_ItemClassName, p_checked string, p_CompareFunc t_CompareFunc, p_Items any, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) DataList(p_Id string, p_CompareFunc t_CompareFunc, p_Items any) *T_HTML
+//  - func (p_HTML *T_HTML) FieldsetOpen(p_Legend string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) Legend(p_Content string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) Label(p_For, p_Content string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) Output(p_name, p_For, p_Content string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) ResetButton(p_label string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) Button(p_name, p_label, p_value string, p_Attributes ...string) *T_HTML
 //  - func BoolField(p_name string, p_checked bool, p_Attributes ...string) string
 //  - func SubmitButton(p_name, p_label, p_value, p_Attributes ...string) string
-//  - func TextField(p_name, p_size, p_maxlen, p_value, p_Attributes ...string) string
+//  - func TextField(p_name, p_size, p_maxlength, p_value, p_Attributes ...string) string, and the functions of all controls above, Fieldset(p_Legend, p_Content string, p_Attributes ...string) instead of FieldsetOpen
+//  - func (p_HTML *T_HTML) SelectMenu(…) *T_HTML, see Select menus
+//
+// Example:
+//
+//   FieldsetOpen("Order").
+//     Label("Qty","Quantity").NumberField("Qty","1","99","","3","id","Qty").
+//     RadioGroup("Breed","choice","RN",nil,map[string]string{"Pekin": "PK", "Rouen": "RN"}).
+//     TextArea("Note","3","40","").
+//   TagCloseTop()
+//
+//
+// # Select menus [File: UTL_HTML_Select]
+//
+// Select() appends a complete `<select>` whose options come from a map - the keys are the labels, the values the values -, from ordered slices of T_Option{Value, Label, Group} or [2]string{value, label}, from slices of values, from slices of structs with the fields KeyField, LabelField and GroupField of T_Select, or from the result set of a query, by default the first column is the value and the second the label. Options with a group are put into `<optgroup>`s, options without group come first. T_Select also holds the selected values, Multiple for several of them, the disabled values, a Placeholder option with the value "" and the sort order. SelectMenu() is the short form for a single selected value; p_Attributes are appended to the `<select>`, with FormErrors() the value the user chose is selected and the message follows the menu.
+//  - func (p_HTML *T_HTML) Select(p_FieldName string, p_Menu T_Select, p_MenuItems any, p_Attributes ...string) *T_HTML
 //  - func (p_HTML *T_HTML) SelectMenu(p_FieldName, p_MenuClassName, p_ItemClassName, p_DefaultValue string, p_CompareFunc t_CompareFunc, p_MenuItems any, p_Attributes ...any) *T_HTML
+//  - func Select(p_FieldName string, p_Menu T_Select, p_MenuItems any, p_Attributes ...string) string
+//
+// Example:
+//
+//
+//   Rows,_ := dbh.Query("SELECT Breed, Class FROM DuckBreeds ORDER BY Class, Breed")
+//   HTML.Select("Breed",T_Select{KeyField: "Breed", GroupField: "Class", Placeholder: "Choose a breed"},Rows,"required")
+//
+//
+// # Forms from structs [File: UTL_HTML_FormStruct]
+//
+// FormStruct() is the counterpart of TrTdStruct() for editing: it appends a `<form>` with a labelled field for each column of a struct, pre-filled with the current values, and a submit button. FormFields() appends only the fields, for forms with more content. The type of a field is derived from the Go type: bool → checkbox, integers and floats → number, time.Time → date (datetime-local if the Layout has a time), everything else → text. The html struct-tag changes this with Input='email|password|textarea|hidden|…', Options='S=Small,M=Medium' makes a select menu (multiple for slices), Label, Placeholder and Required complete the field. Skip, ColHeader, Order and nested structs work as for tables, the columns of a nested struct are put into a `<fieldset>`. The field names are the column names, "Address.City" for nested structs.
+//  - func (p_HTML *T_HTML) FormStruct(p_Action, p_Method, p_FieldClass, p_Submit string, p_DataItem any, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) FormFields(p_FieldClass string, p_DataItem any) *T_HTML
+//
+// Example:
+//
+//
+//  type t_DuckOrder struct {
+//    Id       int       `html:"Input='hidden'"`
+//    Customer string    `html:"Label='Your name' Placeholder='First and last name' Required"`
+//    Email    string    `html:"Input='email'"`
+//    Quantity int
+//    Delivery time.Time
+//    Size     string    `html:"Options='S=Small,M=Medium,L=Large'"`
+//  }
+//  …
+//  v_Doc.FormStruct("/order","post","field","Send",&Order)
+//
+//
+// # Binding request parameters [File: UTL_HTML_Bind]
+//
+// BindRequest() decodes the URL and form values of a request into a struct, the counterpart of FormStruct(). The parameters are matched by the column names of the struct, "Address.City" for nested structs, or the Param of the html struct-tag; Skip fields are left alone, so the same struct type drives TrTdStruct(), FormStruct() and the decoding. The values are converted to the type of the field: ints, uints and floats with strconv, bool with checkbox semantics (true for on, true, yes and 1, false if the parameter is missing), time.Time with the Layout of the struct-tag or the formats of date, datetime-local and time inputs, slices from multi-value parameters, pointers stay nil for missing or empty values, sql.NullString & Co. and other sql.Scanner and encoding.TextUnmarshaler types decode themselves. Missing parameters leave the field unchanged. Conversion errors are returned by parameter name as T_FieldErrors, which also implements the error interface.
+//  - func BindRequest(r *http.Request, p_Target any) T_FieldErrors
+//  - func BindParameters(p_Values url.Values, p_Target any) T_FieldErrors
+//
+// Example:
+//
+//
+//  var Order t_DuckOrder
+//  if Errors := BindRequest(r,&Order); Errors != nil {
+//    … show the form again
+//  }
+//
+//
+// # Form validation [File: UTL_HTML_Valid]
+//
+// Validate() checks a struct, usually right after BindRequest(), against the rules of the html struct-tag and against checks declared in code, and returns the errors by parameter name as T_FieldErrors. The rules of the struct-tag are Required, MinLength and MaxLength for the number of characters, Min and Max for numbers and dates, Pattern for a regular expression that must match the whole value, Input='email' and Input='url', and Options for one of the options; except Required they are only checked for values that are not empty. Checks in code cover the same rules for single fields and rules across fields, like CheckEqual() for a password and its confirmation, CheckField() takes any function. A field keeps its first error.
+//
+// FormErrors() hands the errors and the values the user typed to the form fields until FormErrorsEnd(): the form controls - TextField(), BoolField(), TextArea(), RadioGroup() and the other inputs - SelectMenu() and FormFields() add the class "invalid", aria-invalid and aria-describedby to fields with errors, and all of them and HiddenField() append the message as `<span class="invalid-message" id="name-error">`. FormFields() shows the typed values instead of the values of the struct and adds the HTML5 constraint attributes required, minlength, maxlength, min, max and pattern.
+//  - func Validate(p_Data any, p_Checks ...T_Check) T_FieldErrors
+//  - func (p_Errors T_FieldErrors) Add(p_Name, p_Message string)
+//  - func (p_Errors T_FieldErrors) Merge(p_Other T_FieldErrors) T_FieldErrors
+//  - func CheckField(p_Name string, p_Check func(p_Value any) string) T_Check
+//  - func CheckRequired(p_Names ...string) T_Check
+//  - func CheckLength(p_Name string, p_Min, p_Max int) T_Check
+//  - func CheckRange(p_Name string, p_Min, p_Max string) T_Check
+//  - func CheckPattern(p_Name, p_Pattern string) T_Check
+//  - func CheckEmail(p_Name string) T_Check
+//  - func CheckOneOf(p_Name string, p_Values ...string) T_Check
+//  - func CheckEqual(p_Name, p_Other, p_Message string) T_Check
+//  - func (p_HTML *T_HTML) FormErrors(p_Errors T_FieldErrors, p_Values url.Values) *T_HTML
+//  - func (p_HTML *T_HTML) FormErrorsEnd() *T_HTML
+//
+// Example:
+//
+//
+//   var Signup t_DuckSignup
+//   Errors := BindRequest(r,&Signup).Merge(Validate(&Signup,CheckEqual("Password","Repeat","The passwords differ.")))
+//   if Errors != nil {
+//     HTML.FormErrors(Errors,r.Form).FormStruct("/signup","post","field","Sign up",&Signup).FormErrorsEnd()
+//   }
+//
+//
+// # CSRF protection [File: UTL_HTML_Csrf]
+//
+// Once SetCSRF() has configured a server secret, FormOpen() adds a signed, time-limited token as hidden field GC_CSRFField ("csrf_token") to every form with the method POST, which includes FormStruct(). The token is an HMAC-SHA256 of the session identifier and the time of issue and is valid for MaxAge, 12 hours by default. The Session function, which returns the session identifier of a request, is required with a secret; a POST form of a document without CSRF() or the context of CSRFProtect() gets no token and Err() reports the missing session. VerifyCSRF() accepts GET, HEAD, OPTIONS and TRACE; other requests are refused if Sec-Fetch-Site says cross-site, if the Origin is neither the host of the request nor one of the trusted Origins, or if the token of the form field or of the header GC_CSRFHeader is missing, forged, of another session or expired. A multipart/form-data body is not parsed by VerifyCSRF(): the token is taken from the form fields in the first 8 KB of the body, where FormOpen() puts it, so ReadUploads() and SaveUploads() still apply their limits. CSRFProtect() wraps a handler, answers refused requests with 403 Forbidden and passes the session on in the context of the request, so documents with WithContext(r.Context()) get the tokens of the right session without further code. Without a secret nothing changes.
+//  - func SetCSRF(p_Config T_CSRF)
+//  - func CSRFProtect(p_Next http.Handler) http.Handler
+//  - func VerifyCSRF(r *http.Request) error
+//  - func CSRFToken(p_Session string) string
+//  - func (p_HTML *T_HTML) CSRF(p_Session string) *T_HTML
+//
+// Example:
+//
+//
+//   SetCSRF(T_CSRF{Secret: Secret, Session: SessionID}) // SessionID(r) returns the value of the session cookie
+//   http.Handle("/order",CSRFProtect(http.HandlerFunc(OrderHandler)))
+//   …
+//   New(GC_DocTypeHTML5,0x02).WithContext(r.Context()).FormStruct("/order","post","field","Send",&Order)
+//
+//
+// # File uploads [File: UTL_HTML_Upload]
+//
+// Forms with a FileField() or UploadField() are sent as multipart/form-data; the file input adds enctype="multipart/form-data" to the open FormOpen() by itself. ReadUploads() parses the body with the memory limit MaxMemory of T_Upload - larger files go to temporary files - and returns the accepted files with field, name, size and the content type detected from the first 512 bytes. SaveUploads() streams the files into a directory instead, under names made by os.CreateTemp with the extension of the original name, and stores the other fields in r.Form for BindRequest(). Files larger than MaxSize or of a type not in Types ("image/png", "image/*", …) are not accepted and are reported by the name of their field as T_FieldErrors, which FormErrors() shows next to the file input; a body larger than MaxBody is reported by the name "". Both have to read the body first: a body already parsed by FormValue() or ParseMultipartForm() is refused with an error by the name "", as its limits could no longer be applied. They work behind CSRFProtect(), which doesn't parse multipart bodies. ReadReqParameter() and BindRequest() parse multipart requests too.
+//  - func ReadUploads(r *http.Request, p_Upload T_Upload) ([]T_UploadFile, T_FieldErrors)
+//  - func SaveUploads(r *http.Request, p_Upload T_Upload, p_Dir string) ([]T_UploadFile, T_FieldErrors)
+//  - func (p_File T_UploadFile) Open() (io.ReadCloser, error)
+//  - func (p_HTML *T_HTML) UploadField(p_name string, p_Upload T_Upload, p_multiple bool, p_Attributes ...string) *T_HTML
+//  - func UploadField(p_name string, p_Upload T_Upload, p_multiple bool, p_Attributes ...string) string
+//
+// Example:
+//
+//
+//   Upload := T_Upload{MaxSize: 5 << 20, Types: []string{"image/*"}}
+//   Files,Errors := SaveUploads(r,Upload,"/srv/photos")
+//   if Errors != nil {
+//     HTML.FormErrors(Errors,r.Form).FormOpen("/photos","post").UploadField("Photo",Upload,true).SubmitButton("","Upload","")
+//   }
 //
 //
 // # Lists [File: UTL_HTML_List]
 //
-// 🚧 Currently under construction, TODO: more functions for complex data-types 🚧
+// Lists from data: List() renders slices as <ul> or <ol> (Ordered), maps as <dl> with the keys in <dt> sorted by Compare (CmpAsc by default) and structs as <dl> with the headers of the fields, see the html struct-tag. Elements that are slices, maps or structs again become nested lists up to MaxDepth. ListClass and ItemClass hold the classes per level, the last class is used for all deeper levels. Render can replace the content of single items, an empty result keeps the formatted value. UlSlice(), OlSlice() and DlMap() are shortcuts with the class parameters of the table functions.
 //  - func (p_HTML *T_HTML) OlOpen(p_Attributes ...string) *T_HTML
 //  - func (p_HTML *T_HTML) UlOpen(p_Attributes ...string) *T_HTML
-//  - func (p_HTML *T_HTML) LiOpen(p_Attributes ...string) *T_HTML {
+//  - func (p_HTML *T_HTML) LiOpen(p_Attributes ...string) *T_HTML
 //  - func (p_HTML *T_HTML) Li(p_Content string, p_Attributes ...string) *T_HTML
 //  - func (p_HTML *T_HTML) Lif(p_Class, p_Format string, p_Data ...any) *T_HTML
+//  - func (p_HTML *T_HTML) List(p_List T_List, p_DataItems any) *T_HTML
+//  - func List(p_List T_List, p_DataItems any) string
+//  - func (p_HTML *T_HTML) UlSlice(p_UlClass, p_LiClass string, p_DataItems any) *T_HTML
+//  - func (p_HTML *T_HTML) OlSlice(p_OlClass, p_LiClass string, p_DataItems any) *T_HTML
+//  - func (p_HTML *T_HTML) DlMap(p_DlClass, p_ItemClass string, p_CompareFunc t_CompareFunc, p_DataItems any) *T_HTML
+//
+// Example:
+//
+//   Config := map[string]any{"ponds": []string{"Mill pond","Duck pond"}, "feeding": map[string]string{"morning": "7:00"}}
+//   v_Doc.List(T_List{ListClass: []string{"config","config-sub"}, Render: func(p_Item T_ListItem) string {
+//     if p_Item.Key == "morning" { return Tag("time",fmt.Sprint(p_Item.Value)) }
+//     return ""
+//   }},Config)
+//
+//
+// # Tree views [File: UTL_HTML_Tree]
+//
+// Tree() renders hierarchical data as nested <details>/<summary> elements, which the browser opens and closes without JavaScript. The nodes come from nested maps, slices and structs, the same way as for List(), or from a *sql.Rows with one row per node and the key of its parent (adjacency list): KeyField, ParentField and LabelField default to the first three columns; rows whose parent is NULL, "" or not in the result set are top-level nodes. Nodes without children, empty slices, maps and structs too, are leaves: a <li> with the LeafClass. Open sets the number of expanded levels (-1: all), each node shows the number of its children in a <span> unless NoCount is set. Label can replace the label of single nodes, T_TreeNode holds key, depth, number of children and value; for result sets the value is a map with the columns of the row.
+//  - func (p_HTML *T_HTML) Tree(p_Tree T_Tree, p_DataItems any) *T_HTML
+//  - func Tree(p_Tree T_Tree, p_DataItems any) string
+//
+// Example:
+//
+//
+//   Rows,_ := dbh.Query("SELECT Id, Parent, Name FROM Dirs ORDER BY Name")
+//   v_Doc.Tree(T_Tree{TreeClass: "tree", CountClass: "count", Open: 1},Rows)
+//   Rows.Close()
 //
 //
 // # Tables [File: UTL_HTML_Table]
@@ -212,6 +384,9 @@
 //  - func (p_HTML *T_HTML) TrTdSlice(p_TrClass, p_TdClass string, p_DataRows any) *T_HTML
 //  - func (p_HTML *T_HTML) TrThSqlRows(p_TrClass, p_ThClass string, p_DataRows *sql.Rows) *T_HTML
 //  - func (p_HTML *T_HTML) TrTdSqlRows(p_TrClass, p_TdClass string, p_DataRows *sql.Rows) *T_HTML
+//  - func (p_HTML *T_HTML) Err() error
+//
+// Values of database/sql are converted before they are printed [File: UTL_HTML_Sql]: []byte as text, NULL as empty cell, sql.NullString & Co. by their value, also in structs and slices. The column types decide about the formatting: numbers are right-aligned, DECIMAL columns keep their scale, DATE, TIME and DATETIME columns are printed as 2006-01-02, 15:04:05 and 2006-01-02 15:04:05. The table functions return *T_HTML for chaining, so an error of Rows.Err() or Scan() stops the rows and is returned by Err().
 //
 // Example for a simple static table:
 //
@@ -315,6 +490,176 @@
 //
 // Again, values are being converted into strings using fmt.Sprint, so composite types will be converted into strings according to their Stringer interface.
 //
+// # Query tables [File: UTL_HTML_Sql]
+//
+// QueryTable() replaces the usual sequence of Query, defer Rows.Close(), TheadOpen().TrThSqlRows() and TbodyOpen().TrTdSqlRows() with one call: it runs a query with context and arguments on an *sql.DB, *sql.Conn or *sql.Tx and appends the complete table with caption, header-row and data-rows. MaxRows limits the number of rows, a "truncated" notice in `<tfoot>` tells that there were more. Errors are shown as a row of class "error" and returned by Err(). The number of rows and the time for the query are returned by QueryResult(); the caption and the texts may contain the placeholders {rows}, {max}, {elapsed} and {error}.
+//  - func (p_HTML *T_HTML) QueryTable(ctx context.Context, p_DB T_Queryer, p_Table T_QueryTable, p_Query string, p_Args ...any) *T_HTML
+//  - func (p_HTML *T_HTML) QueryResult() T_QueryResult
+//  - func (p_Result T_QueryResult) Expand(p_Text string) string
+//
+// Example:
+//
+//
+//  v_Doc.QueryTable(r.Context(),dbh,T_QueryTable{Caption: "{rows} breeds in {elapsed}", MaxRows: 100, TableClass: "class4table"},
+//                   "SELECT Breed, Class, Flying FROM DuckBreeds WHERE Class = ? ORDER BY Breed",Class)
+//  if v_Doc.Err() != nil { log.Println(v_Doc.Err()) }
+//
+//
+// # Sortable tables [File: UTL_HTML_Sort]
+//
+// Users can choose the sort column of a table: Sortable() reads the request parameters "sort" and "dir" from the map of ReadReqParameter() and turns the header cells of TrTh(), TrThStruct() and TrThSqlRows() into links carrying the column name and the sort direction, the sorted column gets an aria-sort attribute and an arrow. TrTdSlice() and TrTdMap() sort their rows by that column, SQL queries are sorted with OrderBy(), which accepts only the listed columns; SortOrder() returns just the sort order, e.g. "Breed DESC", for LimitQuery(). The query wrapped by OrderBy() and LimitQuery() should not have an ORDER BY clause of its own: SQL doesn't keep the order of a sub-query and some databases refuse it.
+//  - func (p_HTML *T_HTML) Sortable(p_Parameter map[string]string, p_KeepParams ...string) *T_HTML
+//  - func (p_HTML *T_HTML) SortableEnd() *T_HTML
+//  - func (p_HTML *T_HTML) OrderBy(p_Query string, p_Columns ...string) string
+//  - func (p_HTML *T_HTML) SortOrder(p_Default string, p_Columns ...string) string
+//
+// Example:
+//
+//  Parameter := ReadReqParameter(r)
+//  v_Doc := New(GC_DocTypeHTML5,0x02).Sortable(Parameter)
+//  Rows,err := dbh.Query(v_Doc.OrderBy("SELECT * FROM DuckBreeds","Breed","Class","Flying"))
+//  …
+//  TableOpen("class","class4table").
+//    TheadOpen().
+//      TrThSqlRows("class4tr","class4th",Rows).
+//    TagCloseTop().
+//    TbodyOpen().
+//      TrTdSqlRows("class4tr","class4td",Rows).
+//  …
+//
+//
+// # Pagination [File: UTL_HTML_Page]
+//
+// Large tables are split into pages: Paginate() reads the request parameters "page" and "size", TrTdSlice() and TrTdMap() render only the rows of the current page, after sorting. SQL queries are wrapped with ORDER BY and LIMIT/OFFSET by LimitQuery(), on the same SELECT, the total number of rows for the page numbers is retrieved with CountQuery() and passed to SetPageTotal(). For really large tables KeysetQuery() continues after the key of the last row of the previous page instead of skipping rows with OFFSET. The link to the next page carries the key in "after" and, for numeric keys, the type in "after_type", so a key of ROW_NUMBER() or another computed number is compared as a number again. PageNav() renders the navigation bar, other request parameters are kept in the links.
+//  - func (p_HTML *T_HTML) Paginate(p_Parameter map[string]string, p_PageSize int, p_KeepParams ...string) *T_HTML
+//  - func (p_HTML *T_HTML) PaginateEnd() *T_HTML
+//  - func (p_HTML *T_HTML) SetPageTotal(p_Total int) *T_HTML
+//  - func (p_HTML *T_HTML) LimitQuery(p_Query, p_OrderBy string) string
+//  - func (p_HTML *T_HTML) KeysetQuery(p_Query, p_KeyColumn string) (string, []any)
+//  - func (p_HTML *T_HTML) PageNav(p_Class string, p_Attributes ...string) *T_HTML
+//  - func CountQuery(p_Query string) string
+//
+// Example:
+//
+//  Parameter := ReadReqParameter(r)
+//  v_Doc := New(GC_DocTypeHTML5,0x02).Sortable(Parameter).Paginate(Parameter,25)
+//  dbh.QueryRow(CountQuery("SELECT * FROM DuckBreeds")).Scan(&Total)
+//  Rows,err := dbh.Query(v_Doc.SetPageTotal(Total).LimitQuery("SELECT * FROM DuckBreeds",v_Doc.SortOrder("Breed","Breed","Class")))
+//  …
+//  TableOpen("class","class4table").
+//    TbodyOpen().
+//      TrTdSqlRows("class4tr","class4td",Rows).
+//  TagCloseUntil("table").
+//  PageNav("class4nav").
+//  …
+//
+//
+// # Footer aggregates [File: UTL_HTML_Aggregate]
+//
+// Sums, averages, minimum, maximum, count and count-distinct of table columns are collected while the data-rows are rendered and appended as `<tfoot>` row by TfootAggregates(). For structs the aggregate is defined with the struct-tag `html:"Aggregate='sum'"`, for typed tables with the Aggregate field of T_Column, for all other tables by column name with Aggregate(). The column names are those of the last header-row, the field names of a struct or the column names of an SQL query. The definition may contain a format for the result, e.g. "avg:%.1f", otherwise the Format of the struct-tag is used. Numbers in strings and []byte, as returned by database/sql for DECIMAL columns, are converted; nil values are ignored. Without numeric values sum and count are 0, avg, min and max stay empty. An unknown aggregate function panics when it is defined, for struct-tags when the struct type is used first. With pagination, the aggregates cover the rows of the current page. A new header-row starts new aggregates.
+//  - func (p_HTML *T_HTML) Aggregate(p_Definitions ...string) *T_HTML
+//  - func (p_HTML *T_HTML) AggregateEnd() *T_HTML
+//  - func (p_HTML *T_HTML) TfootAggregates(p_TrClass, p_TdClass, p_Label string) *T_HTML
+//
+// Example:
+//
+//  v_Doc := New(GC_DocTypeHTML5,0x02).Aggregate("Breed","count","Flying","sum")
+//  …
+//  TableOpen("class","class4table").
+//    TrThSqlRows("class4tr","class4th",Rows).
+//    TrTdSqlRows("class4tr","class4td",Rows).
+//    TfootAggregates("class4tr","class4total","Total").
+//  TagCloseUntil("table").
+//  …
+//
+//
+// # Group-by sections [File: UTL_HTML_Group]
+//
+// GroupBy() renders the rows of TrTdSlice(), TrTdMap() and TrTdSqlRows() in groups of consecutive rows with the same values in the group columns. With GC_GroupRowspan the group value is printed once in a cell spanning the rows of the group, with GC_GroupHeader the group columns are hidden and each group starts with a header-row spanning the table. After each group follows a subtotal row with the aggregates of the group (see Footer aggregates) and the label, e.g. "Total %s", the grand total is added by TfootAggregates(). Several group columns form nested groups, the outermost first. A []struct is sorted by the group columns, SQL queries have to be ordered by them. Group header-rows and subtotal rows have the additional classes "group" and "subtotal". In rowspan mode the rowspan attribute is added when a group is complete, progressive flushing waits for it.
+//  - func (p_HTML *T_HTML) GroupBy(p_Mode, p_Label string, p_Columns ...string) *T_HTML
+//  - func (p_HTML *T_HTML) GroupByEnd() *T_HTML
+//
+// Example:
+//
+//  Rows,err := dbh.Query("SELECT Class, Breed, Flying FROM DuckBreeds ORDER BY Class, Breed")
+//  v_Doc := New(GC_DocTypeHTML5,0x02).GroupBy(GC_GroupRowspan,"Total %s","Class").Aggregate("Breed","count","Flying","sum")
+//  …
+//  TableOpen("class","class4table").
+//    TrThSqlRows("class4tr","class4th",Rows).
+//    TbodyOpen().
+//      TrTdSqlRows("class4tr","class4td",Rows).
+//    TagCloseTop().
+//    TfootAggregates("class4tr","class4total","Total").
+//  TagCloseUntil("table").
+//  …
+//
+//
+// # Pivot tables [File: UTL_HTML_Pivot]
+//
+// Pivot() turns long-format data into a matrix: one field gives the row keys, one the column keys and one the values, which are aggregated per cell like the footer aggregates, "sum" by default and "count" without value field. The data can be a []struct or []*struct, a map[K1]map[K2]V with the row keys K1 and the column keys K2, or an *sql.Rows result set. The axes are sorted with CmpAsc or any other compare function, missing cells get a placeholder. Pivot() appends `<thead>`, `<tbody>` and, with column totals, `<tfoot>` to the open table.
+//  - func (p_HTML *T_HTML) Pivot(p_TrClass, p_ThClass, p_TdClass string, p_Pivot T_Pivot, p_DataItems any) *T_HTML
+//
+// Example:
+//
+//  Rows,err := dbh.Query("SELECT Class, Flying FROM DuckBreeds")
+//  …
+//  TableOpen("class","class4table").
+//    Pivot("class4tr","class4th","class4td",T_Pivot{RowField: "Class", ColumnField: "Flying", Empty: "0", RowTotals: true, ColumnTotals: true},Rows).
+//  TagCloseUntil("table").
+//  …
+//
+//
+// # Conditional styling [File: UTL_HTML_Style]
+//
+// Rules add classes, styles and attributes to data-rows and data-cells depending on their values. A row rule gets the row with its index since the last header-row and the values by column name, a cell rule additionally the column name and the value of the cell. The rules are applied by TrTd, TrTdStruct, TrTdSlice, TrTdMap, TrTdSqlRows and typed tables, in the order they were added, and stay active until StyleEnd(). StyleRowHeader() prints the first cell of the data-rows as `<th scope="row">`.
+//  - func (p_HTML *T_HTML) StyleRows(p_Rules ...T_RowRule) *T_HTML
+//  - func (p_HTML *T_HTML) StyleCells(p_Rules ...T_CellRule) *T_HTML
+//  - func (p_HTML *T_HTML) StyleRowHeader() *T_HTML
+//  - func (p_HTML *T_HTML) StyleEnd() *T_HTML
+//  - func RuleZebra(p_EvenClass, p_OddClass string) T_RowRule
+//  - func RuleEquals(p_Column string, p_Value any, p_Class string) T_RowRule
+//  - func RuleNegative(p_Class string) T_CellRule
+//
+// Example:
+//
+//
+//  StyleRows(RuleZebra("even","odd"),RuleEquals("Flying","Yes","flying")).
+//  StyleCells(RuleNegative("negative"),func(p_Row T_RowData, p_Column string, p_Value any) T_Style {
+//    if p_Column == "Weight" && p_Row.Text("Class") == "Heavy" { return T_Style{Style: "font-weight: bold"} }
+//    return T_Style{}
+//  }).
+//  StyleRowHeader().
+//  TableOpen("class","class4table").
+//    TrThSqlRows("class4tr","class4th",Rows).
+//    TrTdSqlRows("class4tr","class4td",Rows).
+//  TagCloseUntil("table").
+//  StyleEnd().
+//
+//
+// # Typed tables [File: UTL_HTML_Typed]
+//
+// Typed tables are an alternative to the reflection-based functions: the columns of a table are defined once with NewTable() as a list of T_Column[T], each with a header and a Value() or Render() function, and the compiler checks them against the row type. A column definition is reused for the header and for the body of a table; rows come from a slice, an iter.Seq[T] (streamed, e.g. from a database cursor) or a map. Format, Layout, Align and Empty work like the keys of the html struct-tag. Sorting and pagination are supported for slices and maps.
+//  - func NewTable[T any](p_Columns ...T_Column[T]) *T_Table[T]
+//  - func (p_Table *T_Table[T]) Rows(p_Rows []T) T_TableBody
+//  - func (p_Table *T_Table[T]) Seq(p_Rows iter.Seq[T]) T_TableBody
+//  - func MapRows[K cmp.Ordered, T any](p_Table *T_Table[T], p_Rows map[K]T) T_TableBody
+//  - func (p_HTML *T_HTML) TheadOf(p_Table T_TableHead) *T_HTML
+//  - func (p_HTML *T_HTML) TbodyOf(p_Rows T_TableBody) *T_HTML
+//
+// Example:
+//
+//  v_Cities := NewTable(
+//    T_Column[*t_CityRec]{Header: "City", Value: func(c *t_CityRec) any { return c.Name }},
+//    T_Column[*t_CityRec]{Header: "Population", Value: func(c *t_CityRec) any { return c.Population }, Align: "right"},
+//  )
+//  …
+//  TableOpen("class","class4table").
+//    TheadOf(v_Cities).
+//    TbodyOf(v_Cities.Rows(CityList)).
+//  TagCloseUntil("table").
+//  …
+//
+//
 // # The html struct-tag
 //
 // Now finally, about the struct-tags in the example t_TickerSymbol structure: When you create your own data-structures, you can add additional information to each field of your structure to control how HTML is generated. The syntax of the html struct-tag:
@@ -323,11 +668,37 @@
 //  - `html:"HeaderClass='CSS class-name'"` - add class-name to <th> tag
 //  - `html:"DataClass='CSS class-name'"`   - add class-name to <td> tag
 //  - `html:"Style="CSS Declaration"`       - add style attribute to <th> or <td> tag
+//  - `html:"Format='%.2f'"`                - format the value with fmt.Sprintf
+//  - `html:"Layout='2006-01-02'"`          - format time.Time values with time.Format
+//  - `html:"Align='right'"`                - text-align of <th> and <td>
+//  - `html:"Width='8em'"`                  - width of the column
+//  - `html:"Order='1'"`                    - position of the column, fields without Order keep their position (1, 2, 3, …), on equal positions the field with Order comes first
+//  - `html:"Empty='—'"`                    - replacement for nil and zero values
+//  - `html:"Link='/ticker/{Name}'"`        - wrap the value into a link, {FieldName} is replaced with the value of that field, {Key} with the map-key
+//  - `html:"Nested='group'"`               - struct fields: flatten, group or string (see below)
+//  - `html:"Aggregate='sum'"`              - footer aggregate of the column: sum, avg, min, max, count or distinct (see Footer aggregates)
+//  - `html:"Label='Text'"`                 - label of the form field instead of ColHeader or field-name (see Forms from structs)
+//  - `html:"Input='email'"`                - type of the form field: text, email, password, textarea, hidden, …, default is derived from the type of the field
+//  - `html:"Placeholder='Text'"`           - placeholder of the form field
+//  - `html:"Options='S=Small,M=Medium'"`   - the form field is a select menu: values or value=label pairs, separated by commas
+//  - `html:"Required"`                     - the form field is required
+//  - `html:"Param='q'"`                    - name of the request parameter and the form field instead of the field-name (see Binding request parameters)
+//  - `html:"MinLength='3'"`                - validation and form field: minimum number of characters (see Form validation)
+//  - `html:"MaxLength='40'"`               - validation and form field: maximum number of characters
+//  - `html:"Min='1'"`                      - validation and form field: minimum number or date
+//  - `html:"Max='99'"`                     - validation and form field: maximum number or date
+//  - `html:"Pattern='[A-Z]{3}'"`           - validation and form field: regular expression for the whole value, a quote is written `\\'`, checked when the struct is used first
 //
 // All combinations are supported, for example:
 // `html:"ColHeader='PrimaryKey'" HeaderClass='Centered' DataClass='Centered' Style='color: red;'`
 //
-// The keywords "ColHeader", "HeaderClass", "DataClass", "Skip" and "Style" are case insensitive, the tag-name "html" is case-sensitive.
+// The keywords are case insensitive, the tag-name "html" is case-sensitive.
+//
+// Fields of embedded structs are promoted the way encoding/json does it: they become columns of their own, on name conflicts the field of the outer struct wins. Nested struct fields, for example an Address inside a Customer, become grouped columns: TrThStruct() generates two header-rows with the name of the struct field as group label spanning the columns of the nested struct. With `html:"Nested='flatten'"` the columns are added without group label, with `html:"Nested='string'"` the struct is printed into a single column. Types with a String() method and time.Time are always printed into a single column. Pointers to structs are printed into a single column too, unless Nested is set; a struct type is never expanded inside itself, so a field like Parent *Dir in the struct Dir remains one column.
+//
+// The struct-tags are analyzed only once per struct-type, the resulting column definitions are cached and shared by all table functions and goroutines.
+//
+// The formatting keywords are honoured by TrThStruct() and TrTdStruct() and therefore by TrTdSlice() and TrTdMap() as well, for example `html:"Format='%.2f' Align='right'"` prints a float32 price with two decimals, right aligned.
 //
 // Please use the html struct-tag only as last resort: Remember the the »Separation of Concerns«:
 //
@@ -396,8 +767,82 @@
 // For more examples see the files: »UTL_HTML_*test.go«
 //
 //
-// # CGI functions
-//  - func ReadReqParameter(r *http.Request) map[string]string
+// # CGI functions [File: UTL_HTML_Params]
+//
+// ReadParams() reads the parameters of a request and keeps their sources apart: Header, Query, Form, Cookie and the Basic-auth credentials in Auth, so a form field named "UserName" can't pose as the user. The body may be urlencoded, multipart or JSON; the members of a JSON object become form fields ("address.city" for nested objects, arrays are multi-values) and the decoded body is kept in JSON. The getters String(), Int(), Int64(), Float(), Bool() and Time() return the first value or the default for missing and empty parameters, Strings() all values of a multi-value parameter, ParamValue() any type BindParameters() can decode. Values that can't be converted give the default and are collected in Errors(). Map() returns the URL and form values for Paginate() and Sortable(). ReadReqParameter() remains as a compatibility shim with its old behaviour: one map of headers, credentials and form fields, multi-values joined by ";", panics on errors.
+//  - func ReadParams(r *http.Request) (*T_Params, error)
+//  - func (p_Params *T_Params) Errors() T_FieldErrors
+//  - func (p_Params *T_Params) Map() map[string]string
+//  - func (p_Values T_ParamValues) String(p_Name, p_Default string) string, Int, Int64, Float, Bool, Time
+//  - func (p_Values T_ParamValues) Strings(p_Name string) []string
+//  - func (p_Values T_ParamValues) Has(p_Name string) bool
+//  - func (p_Values T_ParamValues) Names() []string
+//  - func ParamValue[T any](p_Values T_ParamValues, p_Name string, p_Default T) T
+//  - func ReadReqParameter(r *http.Request) map[string]string, deprecated
+//
+// Example:
+//
+//
+//   Params,err := ReadParams(r)
+//   if err != nil { … 400 Bad Request }
+//   Page  := Params.Query.Int("page",1)
+//   Since := Params.Query.Time("since",time.Now().AddDate(0,-1,0))
+//   if Params.Header.Bool("HX-Request",false) { … }
+//
+//
+// # Authentication [File: UTL_HTML_Auth]
+//
+// RequireAuth() wraps a handler with Basic authentication: requests without valid credentials are answered with 401 Unauthorized, a WWW-Authenticate challenge for the realm and a small page with GC_AuthTitle and GC_AuthMessage. The credentials are checked by a T_Verifier: T_UserMap for a map of users and passwords, LoadHtpasswd() for a file with salted SHA-256 hashes (lines created by HtpasswdLine()), or T_VerifierFunc for any other lookup. Passwords are compared in constant time, unknown users take as long as known users. The authenticated user is passed on in the context of the request: AuthUser(r) in the handler, User() in documents built with WithContext(r.Context()).
+//  - func RequireAuth(p_Realm string, p_Verifier T_Verifier, p_Next http.Handler) http.Handler
+//  - func AuthUser(r *http.Request) string
+//  - func (p_HTML *T_HTML) User() string
+//  - func LoadHtpasswd(p_File string) (*T_Htpasswd, error)
+//  - func HtpasswdLine(p_User, p_Password string) string
+//  - type T_UserMap map[string]string
+//  - type T_VerifierFunc func(p_User, p_Password string) bool
+//
+// Example:
+//
+//
+//   Users,err := LoadHtpasswd("/etc/ducks/htpasswd")
+//   if err != nil { log.Fatal(err) }
+//   http.Handle("/pond",RequireAuth("Duck Pond",Users,http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//     New(GC_DocTypeHTML5,0x02).WithContext(r.Context()).
+//       HtmlOpen().
+//         BodyOpen().
+//           Pf("","Welcome %s",AuthUser(r)).
+//       CloseTagsAndWrite(w)
+//   })))
+//
+//
+// # htmx [File: UTL_HTML_Htmx]
+//
+// Pages using htmx request only a fragment of the page when the HX-Request header is present. A region of the document is opened with RegionOpen(id) as <div id="…">, WriteHx() writes the full page for normal requests and only the content of the region for htmx-requests, so the same builder chain serves both. The hx-* attribute helpers return attribute/value pairs which are combined with Attributes().
+//  - func (p_HTML *T_HTML) RegionOpen(p_Id string, p_Attributes ...string) *T_HTML
+//  - func (p_HTML *T_HTML) Region(p_Id string) string
+//  - func (p_HTML *T_HTML) WriteHx(w http.ResponseWriter, r *http.Request, p_Id string)
+//  - func IsHxRequest(r *http.Request) bool
+//  - func Attributes(p_Lists ...[]string) []string
+//  - func HxGet(p_URL string) []string, HxPost, HxPut, HxPatch, HxDelete
+//  - func HxTarget(p_Selector string) []string, HxSelect, HxIndicator
+//  - func HxSwap(p_Swap string) []string // GC_HxSwapInnerHTML, GC_HxSwapOuterHTML, …
+//  - func HxTrigger(p_Trigger string) []string, HxPushURL, HxConfirm
+//  - func SetHxTrigger(w http.ResponseWriter, p_Event string)
+//  - func SetHxRedirect(w http.ResponseWriter, p_URL string)
+//  - func SetHxRefresh(w http.ResponseWriter), SetHxPushURL, SetHxRetarget, SetHxReswap
+//
+// Example:
+//
+//  New(GC_DocTypeHTML5,0x02).
+//    HtmlOpen().
+//      BodyOpen().
+//        Header("1","Ducks").
+//        Tag("input","",Attributes([]string{"type","search","name","q"},HxGet("/ducks"),HxTarget("#result"),HxTrigger("keyup changed delay:300ms"))...).
+//        RegionOpen("result").
+//          TableOpen().
+//            TbodyOpen().
+//              TrTdSqlRows("","",Rows).
+//    WriteHx(w,r,"result")
 //
 //
 // # Other Functions
@@ -412,14 +857,17 @@
 package UTL_HTML
 
 import (
+  "time"
+  "context"
   "strings"
-  "encoding/base64"
   "fmt"
   "cmp"
   "net/http"
+  "net/url"
   "bytes"
   "regexp"
   "reflect"
+  "strconv"
 )
 
 const (
@@ -445,6 +893,60 @@ type T_HTML struct {
                         // 0x01: NL after every closing tag
                         // 0x02: NL after every complete tag
                         // 0x04: NL after every opening tag
+
+  flushWriter   http.ResponseWriter // target for progressive flushing, nil: no flushing
+  flushRows     int                 // flush after n table-rows, 0: no row limit
+  flushInterval time.Duration       // flush after a time interval, 0: no time limit
+  flushCount    int                 // table-rows since the last flush
+  flushTime     time.Time           // time of the last flush
+  flushFailed   bool                // writing to the flush target failed, no more rows are generated
+  ctx           context.Context     // request context, table functions stop when it is cancelled
+
+  regions map[string]*t_Region      // named regions of the document, see RegionOpen
+  flushed int                       // bytes written by Flush, the offsets of the regions count from the start of the document
+
+  sortActive  bool                  // header cells become sort-links, see Sortable
+  sortColumn  string                // name of the column to sort by
+  sortDesc    bool                  // true: descending sort-order
+  columnNames []string              // column-names of the last header-row
+  linkParams  map[string]string     // request parameters that are kept in generated links
+
+  pageActive  bool                  // data-rows are split into pages, see Paginate
+  pageNum     int                   // current page, starting with 1
+  pageSize    int                   // number of rows per page
+  pageTotal   int                   // total number of rows, -1: unknown
+  keyColumn   string                // keyset pagination: column with the unique sort key
+  keyAfter    string                // keyset pagination: key of the last row of the previous page
+  keyType     string                // keyset pagination: "int" or "float" for a numeric keyAfter, see KeysetQuery
+  keyNext     string                // keyset pagination: key of the last row of the current page
+  keyNextType string                // keyset pagination: "int" or "float" for a numeric keyNext
+  keyMore     bool                  // keyset pagination: there are more rows after the current page
+
+  aggDefs     map[string]string     // aggregate definitions by column name, see Aggregate
+  aggColumns  []*t_Aggregate        // aggregates of the current table by column position, nil: none
+
+  groupMode   string                // GC_GroupRowspan or GC_GroupHeader, empty: no grouping, see GroupBy
+  groupLabel  string                // label of the subtotal rows
+  groupLevels []*t_GroupLevel       // the group columns, outermost first
+  groupNames  []string              // column-names of the last grouped data-row
+
+  rowRules    []T_RowRule           // styling rules for data-rows, see StyleRows
+  cellRules   []T_CellRule          // styling rules for data-cells, see StyleCells
+  rowHeader   bool                  // the first cell of the data-rows is <th scope="row">
+  rowIndex    int                   // data-rows since the last header-row
+  cellBuffer  []t_Cell              // the cells of the current data-row, reused
+
+  err         error                 // first error of the table functions, see Err
+  sqlMax      int                   // QueryTable: maximum number of rows of TrTdSqlRows, 0: no limit
+  sqlRows     int                   // number of rows of the last TrTdSqlRows
+  sqlMore     bool                  // the last TrTdSqlRows stopped at sqlMax, there are more rows
+  queryResult T_QueryResult         // result of the last QueryTable, see QueryResult
+  captionHold bool                  // QueryTable: the caption is inserted when the table is complete
+
+  formErrors  T_FieldErrors         // errors shown by the form fields, see FormErrors
+  formValues  url.Values            // the values the user typed, shown by fields with errors
+  csrfSession *string               // session of the CSRF tokens, nil: session of CSRFProtect, see CSRF
+  formOffset  int                   // position after "<form" of the open form without enctype, 0: none, see FileField
 } // END T_HTML
 
 
@@ -469,6 +971,9 @@ func (p_HTML *T_HTML) popTag() string {
   LastIndex := len(p_HTML.tagStack) - 1
   TagName := p_HTML.tagStack[LastIndex]
   p_HTML.tagStack = p_HTML.tagStack[:LastIndex]
+  if TagName == "form" {
+    p_HTML.formOffset = 0 // a FileField after the form must not change its enctype
+  } // END if
   return TagName
 } // END popTag
 
@@ -554,6 +1059,70 @@ func (p_HTML *T_HTML) Len()int {
   return p_HTML.content.Len()
 } // END Len
 
+// Enable progressive flushing: The table functions write the buffered content to w and flush it
+// to the browser after every p_Rows table-rows or after p_Interval has passed, whatever comes first.
+// A value of 0 disables the respective limit. Nothing happens if w doesn't implement http.Flusher.
+// Flushed content is removed from the buffer, Write() and CloseTagsAndWrite() send the rest.
+func (p_HTML *T_HTML) FlushEvery(w http.ResponseWriter, p_Rows int, p_Interval time.Duration) *T_HTML {
+  if _,ok := w.(http.Flusher); !ok {
+    return p_HTML
+  } // END if
+  p_HTML.flushWriter   = w
+  p_HTML.flushRows     = p_Rows
+  p_HTML.flushInterval = p_Interval
+  p_HTML.flushCount    = 0
+  p_HTML.flushTime     = time.Now()
+  return p_HTML
+} // END FlushEvery
+
+// Write the buffered content to the flush target and flush it to the browser
+func (p_HTML *T_HTML) Flush() *T_HTML {
+  if p_HTML.flushWriter == nil || p_HTML.Cancelled() {
+    return p_HTML
+  } // END if
+  if _,Error := p_HTML.flushWriter.Write(p_HTML.content.Bytes()); Error != nil {
+    p_HTML.setError(Error)
+    p_HTML.flushFailed = true // the client is gone like with a cancelled context
+    return p_HTML
+  } // END if
+  p_HTML.flushWriter.(http.Flusher).Flush()
+  p_HTML.flushed += p_HTML.content.Len()
+  p_HTML.content.Reset()
+  p_HTML.formOffset = 0 // the form has been written, its enctype can't be changed any more
+  p_HTML.flushCount = 0
+  p_HTML.flushTime  = time.Now()
+  return p_HTML
+} // END Flush
+
+// Set the context of the request, usually r.Context(). The table functions stop
+// generating rows as soon as the context is cancelled, e.g. when the client disconnects.
+func (p_HTML *T_HTML) WithContext(ctx context.Context) *T_HTML {
+  p_HTML.ctx = ctx
+  return p_HTML
+} // END WithContext
+
+// Return true if the context of the document has been cancelled or writing to the flush target failed
+func (p_HTML *T_HTML) Cancelled() bool {
+  return p_HTML.flushFailed || (p_HTML.ctx != nil && p_HTML.ctx.Err() != nil)
+} // END Cancelled
+
+// Count a generated table-row and flush when one of the limits is reached
+// not exported
+func (p_HTML *T_HTML) rowDone() *T_HTML {
+  if p_HTML.flushWriter == nil {
+    return p_HTML
+  } // END if
+  p_HTML.flushCount++
+  if p_HTML.groupPending() || p_HTML.captionHold || p_HTML.regionOpen() {
+    return p_HTML // the rowspan of the current group, the caption of the table or a region for WriteHx is still open
+  } // END if
+  if (p_HTML.flushRows > 0 && p_HTML.flushCount >= p_HTML.flushRows) ||
+     (p_HTML.flushInterval > 0 && time.Since(p_HTML.flushTime) >= p_HTML.flushInterval) {
+    p_HTML.Flush()
+  } // END if
+  return p_HTML
+} // END rowDone
+
 // -------------------------------------------
 // ********** Generic tag functions **********
 // -------------------------------------------
@@ -684,6 +1253,7 @@ func (p_HTML *T_HTML) TagOpen(p_TagName string, p_Attributes ...string) *T_HTML
 // Close the tag on the top of the stack
 func (p_HTML *T_HTML) TagCloseTop() *T_HTML {
   if TagName := p_HTML.popTag(); TagName != "" {
+    p_HTML.regionEnd()
     p_HTML.AS("</" + TagName + ">")
   } // END if
   if (p_HTML.nlMode & 0x01) != 0 {
@@ -710,6 +1280,7 @@ func (p_HTML *T_HTML) TagCloseUntil(p_Name string) *T_HTML {
     if TagName := p_HTML.popTag(); TagName == "" {
       break // stack is empty
     } else {
+      p_HTML.regionEnd()
       p_HTML.AS("</" + TagName + ">")
       if (p_HTML.nlMode & 0x01) != 0 {
         p_HTML.NL()
@@ -866,31 +1437,31 @@ func (p_HTML *T_HTML) ENDWHEN() *T_HTML {
 // -----------------------------------------
 
 // Copy the headers, url-parameters and post-data into a string-map
+// Compatibility shim for ReadParams: form fields overwrite headers, the credentials "UserCred", "UserName"
+// and "UserAuth" overwrite form fields, multi-values are separated by ";", JSON bodies are not read and
+// errors of the request panic.
+// Deprecated: use ReadParams, which keeps the sources apart.
 func ReadReqParameter(r *http.Request) map[string]string {
-  // create new parameter-list
+  Params,Error := readParams(r,false)
+  if Error != nil { panic(Error) }
   Result := make(map[string]string)
-  
+
   // copy the header
-  for Name,Values := range(r.Header) {
-    Result[Name] = strings.Join(Values,";") // KISS: Multiple values are separated by ";"
-    if Name == "Authorization" {
-      // Try to decode the basic authentication header and store resulty into parameterlist
-      if  Credentials, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(Result[Name], "Basic ")); err == nil {
-        Result["UserCred"] = string(Credentials)
-        if parts := strings.SplitN(string(Credentials), ":", 2); len(parts) == 2 {
-          Result["UserName"] = parts[0]
-          Result["UserAuth"] = parts[1]
-        } // END if
-      } // END if
-    } // END if
+  for _,Name := range Params.Header.Names() {
+    Result[Name] = strings.Join(Params.Header.Strings(Name),";") // KISS: Multiple values are separated by ";"
   } // END for
-  
+
   // URL and POST Data
-  if Error := r.ParseForm(); Error != nil { panic(Error) }
-  for Name,Values := range(r.Form) {
-    Result[Name] = strings.Join(Values,";") // KISS: Multiple values are separated by ";"
+  for Name,Value := range Params.Map() {
+    Result[Name] = Value
   } // END for
 
+  // the credentials can't be overwritten by form fields
+  if Params.Auth.OK {
+    Result["UserCred"] = Params.Auth.User + ":" + Params.Auth.Password
+    Result["UserName"] = Params.Auth.User
+    Result["UserAuth"] = Params.Auth.Password
+  } // END if
   return Result
 } // END ReadReqParameter
 
@@ -903,6 +1474,26 @@ func ReadReqParameter(r *http.Request) map[string]string {
 // `html:"HeaderClass='CSS class-name'"` - add class-name to <th> tag
 // `html:"DataClass='CSS class-name'"`   - add class-name to <td> tag
 // `html:"Style="CSS Declaration"`       - add style attribute to <th> or <td> tag
+// `html:"Format='%.2f'"`                - format the value with fmt.Sprintf
+// `html:"Layout='2006-01-02'"`          - format time.Time values with time.Format
+// `html:"Align='right'"`                - text-align of <th> and <td>
+// `html:"Width='8em'"`                  - width of the column
+// `html:"Order='1'"`                    - position of the column, default is the position of the field
+// `html:"Empty='—'"`                    - replacement for nil and zero values
+// `html:"Link='/ticker/{Name}'"`        - wrap the value into a link, {FieldName} is replaced with the value of the field, {Key} with the map-key
+// `html:"Nested='group'"`               - struct fields: flatten, group or string
+// `html:"Aggregate='sum'"`              - footer aggregate: sum, avg, min, max, count or distinct, see TfootAggregates
+// `html:"Label='Text'"`                 - label of the form field, default is the column header
+// `html:"Input='email'"`                - type of the form field, default is derived from the type of the field
+// `html:"Placeholder='Text'"`           - placeholder of the form field
+// `html:"Options='S=Small,M=Medium'"`   - options of a select menu: values or value=label pairs, separated by commas
+// `html:"Required"`                     - the form field is required
+// `html:"Param='q'"`                    - name of the request parameter and the form field, default is the column name
+// `html:"MinLength='3'"`                - validation: minimum number of characters, see Validate
+// `html:"MaxLength='40'"`               - validation: maximum number of characters
+// `html:"Min='1'"`                      - validation: minimum number or date
+// `html:"Max='99'"`                     - validation: maximum number or date
+// `html:"Pattern='[A-Z]{3}'"`           - validation: regular expression for the whole value, a quote is written `\\'`
 //
 // All combinations are supported, for example:
 // `html:"ColHeader='PrimaryKey'" HeaderClass='Centered' DataClass='Centered' Style='color: red;'`
@@ -912,27 +1503,137 @@ var regExp_ColHeader = regexp.MustCompile(`(?i)ColHeader='(.*?)'`)
 var regExp_HeaderClass = regexp.MustCompile(`(?i)HeaderClass='(.*?)'`)
 var regExp_DataClass = regexp.MustCompile(`(?i)DataClass='(.*?)'`)
 var regExp_Style = regexp.MustCompile(`(?i)Style='(.*?)'`)
-var regExp_Skip = regexp.MustCompile(`(?i)Skip`)
-
-func analyzeHtmlStructTag(p_HtmlStructTag string) (HeaderText, HeaderClass, DataClass, Style string, Skip bool) {
-  if Skip = regExp_Skip.MatchString(p_HtmlStructTag); Skip {
+var regExp_Format = regexp.MustCompile(`(?i)Format='(.*?)'`)
+var regExp_Layout = regexp.MustCompile(`(?i)Layout='(.*?)'`)
+var regExp_Align = regexp.MustCompile(`(?i)Align='(.*?)'`)
+var regExp_Width = regexp.MustCompile(`(?i)Width='(.*?)'`)
+var regExp_Order = regexp.MustCompile(`(?i)Order='(-?[0-9]+)'`)
+var regExp_Empty = regexp.MustCompile(`(?i)Empty='(.*?)'`)
+var regExp_Link = regexp.MustCompile(`(?i)Link='(.*?)'`)
+var regExp_Nested = regexp.MustCompile(`(?i)Nested='(.*?)'`)
+var regExp_Aggregate = regexp.MustCompile(`(?i)Aggregate='(.*?)'`)
+var regExp_Label = regexp.MustCompile(`(?i)(^|\s)Label='(.*?)'`)
+var regExp_Input = regexp.MustCompile(`(?i)Input='(.*?)'`)
+var regExp_Placeholder = regexp.MustCompile(`(?i)Placeholder='(.*?)'`)
+var regExp_Options = regexp.MustCompile(`(?i)Options='(.*?)'`)
+var regExp_Param = regexp.MustCompile(`(?i)Param='(.*?)'`)
+var regExp_MinLength = regexp.MustCompile(`(?i)MinLength='([0-9]+)'`)
+var regExp_MaxLength = regexp.MustCompile(`(?i)MaxLength='([0-9]+)'`)
+var regExp_Min = regexp.MustCompile(`(?i)(^|\s)Min='(.*?)'`)
+var regExp_Max = regexp.MustCompile(`(?i)(^|\s)Max='(.*?)'`)
+var regExp_Pattern = regexp.MustCompile(`(?i)Pattern='((?:[^'\\]|\\.)*)'`) // a quote within the pattern is written \'
+var regExp_TagEscape = regexp.MustCompile(`\\.`)
+var regExp_Skip = regexp.MustCompile(`(?i)(^|\s)Skip(\s|$)`)
+var regExp_Required = regexp.MustCompile(`(?i)(^|\s)Required(\s|$)`)
+
+// Result of the analysis of the html struct-tag
+type t_HtmlTag struct {
+  HeaderText  string
+  HeaderClass string
+  DataClass   string
+  Style       string
+  Format      string
+  Layout      string
+  Align       string
+  Width       string
+  Order       int
+  HasOrder    bool
+  Empty       string
+  Link        string
+  Nested      string
+  Aggregate   string
+  Label       string
+  Input       string
+  Placeholder string
+  Options     string
+  Param       string
+  MinLength   string
+  MaxLength   string
+  Min         string
+  Max         string
+  Pattern     string
+  Required    bool
+  Skip        bool
+} // END t_HtmlTag
+
+func analyzeHtmlStructTag(p_HtmlStructTag string) (Result t_HtmlTag) {
+  if Result.Skip = regExp_Skip.MatchString(p_HtmlStructTag); Result.Skip {
     return //
   } // END if
-  if match := regExp_ColHeader.FindStringSubmatch(p_HtmlStructTag); len(match) > 1 {
-    HeaderText = match[1]
+  Values := []struct{ regExp *regexp.Regexp; value *string }{
+    {regExp_ColHeader,   &Result.HeaderText},
+    {regExp_HeaderClass, &Result.HeaderClass},
+    {regExp_DataClass,   &Result.DataClass},
+    {regExp_Style,       &Result.Style},
+    {regExp_Format,      &Result.Format},
+    {regExp_Layout,      &Result.Layout},
+    {regExp_Align,       &Result.Align},
+    {regExp_Width,       &Result.Width},
+    {regExp_Empty,       &Result.Empty},
+    {regExp_Link,        &Result.Link},
+    {regExp_Nested,      &Result.Nested},
+    {regExp_Aggregate,   &Result.Aggregate},
+    {regExp_Input,       &Result.Input},
+    {regExp_Placeholder, &Result.Placeholder},
+    {regExp_Options,     &Result.Options},
+    {regExp_Param,       &Result.Param},
+    {regExp_MinLength,   &Result.MinLength},
+    {regExp_MaxLength,   &Result.MaxLength},
+    {regExp_Pattern,     &Result.Pattern},
+  }
+  for _,Value := range Values {
+    if match := Value.regExp.FindStringSubmatch(p_HtmlStructTag); len(match) > 1 {
+      *Value.value = match[1]
+    } // END if
+  } // END for
+  if match := regExp_Label.FindStringSubmatch(p_HtmlStructTag); len(match) > 2 {
+    Result.Label = match[2]
   } // END if
-  if match := regExp_HeaderClass.FindStringSubmatch(p_HtmlStructTag); len(match) > 1 {
-    HeaderClass = match[1]
+  if match := regExp_Min.FindStringSubmatch(p_HtmlStructTag); len(match) > 2 {
+    Result.Min = match[2]
   } // END if
-  if match := regExp_DataClass.FindStringSubmatch(p_HtmlStructTag); len(match) > 1 {
-    DataClass = match[1]
+  if match := regExp_Max.FindStringSubmatch(p_HtmlStructTag); len(match) > 2 {
+    Result.Max = match[2]
   } // END if
-  if match := regExp_Style.FindStringSubmatch(p_HtmlStructTag); len(match) > 1 {
-    Style = match[1]
+  Result.Pattern = regExp_TagEscape.ReplaceAllStringFunc(Result.Pattern,func(p_Escape string) string {
+    if p_Escape == `\'` {
+      return "'"
+    } // END if
+    return p_Escape // an escape of the regular expression
+  })
+  Result.Required = regExp_Required.MatchString(p_HtmlStructTag)
+  if match := regExp_Order.FindStringSubmatch(p_HtmlStructTag); len(match) > 1 {
+    Result.Order,_ = strconv.Atoi(match[1])
+    Result.HasOrder = true
   } // END if
   return
 } // END analyzeHtmlStructTag
 
+// Style attribute of the header cell
+func (p_Tag t_HtmlTag) headerStyle() string {
+  return joinStyle("", "text-align", p_Tag.Align, "width", p_Tag.Width)
+} // END headerStyle
+
+// Style attribute of the data cell
+func (p_Tag t_HtmlTag) dataStyle() string {
+  return joinStyle(p_Tag.Style, "text-align", p_Tag.Align)
+} // END dataStyle
+
+// Append CSS property/value pairs with non-empty values to a style declaration
+func joinStyle(p_Style string, p_Pairs ...string) string {
+  for index := 0; index+1 < len(p_Pairs); index += 2 {
+    if p_Pairs[index+1] == "" { continue }
+    if p_Style != "" && !strings.HasSuffix(strings.TrimSpace(p_Style),";") {
+      p_Style += ";"
+    } // END if
+    if p_Style != "" {
+      p_Style += " "
+    } // END if
+    p_Style += p_Pairs[index] + ": " + p_Pairs[index+1] + ";"
+  } // END for
+  return p_Style
+} // END joinStyle
+
 // ---------------------------------------------------------------
 // ********** sort functions for reflect.Value elements **********
 // ---------------------------------------------------------------
@@ -0,0 +1,272 @@
+package UTL_HTML
+//
+// UTL_HTML_Aggregate
+// Version: $Id$
+//
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"reflect"
+)
+
+// *************************************************************************************
+// Footer aggregates: sum, avg, min, max, count and count-distinct of table columns.
+// The values are collected while the data-rows are rendered, TfootAggregates appends
+// the results as <tfoot> row. Aggregates are defined per column with the struct-tag
+// `html:"Aggregate='sum'"`, the Aggregate field of T_Column or by column name with
+// Aggregate(), the latter for slices, maps and SQL result sets.
+//
+// The definition is the aggregate function, optionally followed by a format for the
+// result: "sum", "avg:%.1f", "count", "distinct". Without format, the Format of the
+// struct-tag is used if it fits, whole numbers are printed without and other numbers
+// with two decimals.
+
+const (
+	GC_AggSum      string = "sum"
+	GC_AggAvg      string = "avg"
+	GC_AggMin      string = "min"
+	GC_AggMax      string = "max"
+	GC_AggCount    string = "count"    // number of values that are not nil
+	GC_AggDistinct string = "distinct" // number of distinct values that are not nil
+) // END const
+
+// Aggregate of one table column
+type t_Aggregate struct {
+	kind     string          // one of the GC_Agg constants
+	format   string          // fmt.Sprintf format of the result, empty: default formatting
+	style    string          // style attribute of the footer cell, taken from the column
+	count    int             // number of values that are not nil
+	numbers  int             // number of numeric values
+	sum      float64
+	min      float64
+	max      float64
+	distinct map[string]bool // the distinct values for GC_AggDistinct
+} // END t_Aggregate
+
+var regExp_FormatVerb = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]*)?([a-zA-Z])`)
+
+// Define aggregates by column name: pairs of column name and definition, for example
+// Aggregate("Population","sum","Name","count"). The names are the column names of the
+// header-row, the field names of structs or the column names of SQL result sets.
+// The definitions stay active until AggregateEnd, struct-tags take precedence.
+func (p_HTML *T_HTML) Aggregate(p_Definitions ...string) *T_HTML {
+	if p_HTML.aggDefs == nil {
+		p_HTML.aggDefs = make(map[string]string)
+	} // END if
+	for index := 0; index+1 < len(p_Definitions); index += 2 {
+		parseAggregate(p_Definitions[index+1],"") // check the definition now, not while rendering
+		p_HTML.aggDefs[p_Definitions[index]] = p_Definitions[index+1]
+	} // END for
+	return p_HTML
+} // END Aggregate
+
+// Remove the aggregates defined with Aggregate
+func (p_HTML *T_HTML) AggregateEnd() *T_HTML {
+	p_HTML.aggDefs = nil
+	p_HTML.aggColumns = nil
+	return p_HTML
+} // END AggregateEnd
+
+// Append <tfoot> with one row of the aggregates collected since the last header-row.
+//  - p_TrClass - CSS classname for <tr>
+//  - p_TdClass - CSS classname for <th> and <td>
+//  - p_Label - content of the first cell, if the first column has no aggregate, e.g. "Total"
+//
+// The aggregate function is added as data-aggregate attribute to the cells.
+// With GroupBy the footer is the grand total of all groups.
+func (p_HTML *T_HTML) TfootAggregates(p_TrClass, p_TdClass, p_Label string) *T_HTML {
+	NumColumns := max(len(p_HTML.columnNames),len(p_HTML.aggColumns))
+	p_HTML.TfootOpen().helper_TrOpen(p_TrClass)
+	for column := 0; column < NumColumns; column++ {
+		if p_HTML.groupHidden(p_HTML.columnName(column)) { continue }
+		var Aggregate *t_Aggregate
+		if column < len(p_HTML.aggColumns) {
+			Aggregate = p_HTML.aggColumns[column]
+		} // END if
+		switch {
+			case Aggregate != nil: p_HTML.helper_Tag("td",Aggregate.result(),p_TdClass,"",Aggregate.style,"data-aggregate",Aggregate.kind)
+			case p_Label != "": p_HTML.helper_Tag("th",p_Label,p_TdClass,"","","scope","row")
+			default: p_HTML.helper_Td("&nbsp;",p_TdClass,"","")
+		} // END switch
+		p_Label = "" // only in the first cell
+	} // END for
+	p_HTML.aggColumns = nil
+	return p_HTML.TagCloseTop().TagCloseTop() // tr, tfoot
+} // END TfootAggregates
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// A new header-row starts a new table: forget the column names, the aggregates and the row count of the last one
+func (p_HTML *T_HTML) headerStart() {
+	p_HTML.columnNames = p_HTML.columnNames[:0]
+	p_HTML.aggColumns = nil
+	p_HTML.rowIndex = 0
+} // END headerStart
+
+// Name of the column at position p_Column in the last header-row
+func (p_HTML *T_HTML) columnName(p_Column int) string {
+	if p_Column < len(p_HTML.columnNames) {
+		return p_HTML.columnNames[p_Column]
+	} // END if
+	return ""
+} // END columnName
+
+// Add the value of a cell to the aggregate of its column, if the column has one.
+//  - p_Column - position of the column in the row
+//  - p_Name - name of the column for the definitions of Aggregate
+//  - p_Tag - html struct-tag of the column, Aggregate and Format are used
+//  - p_Style - style attribute of the column
+func (p_HTML *T_HTML) aggregateValue(p_Column int, p_Name string, p_Tag t_HtmlTag, p_Style string, p_Value reflect.Value) {
+	if p_Tag.Aggregate == "" && p_HTML.aggDefs == nil {
+		return // nothing to do, the common case
+	} // END if
+	for len(p_HTML.aggColumns) <= p_Column {
+		p_HTML.aggColumns = append(p_HTML.aggColumns,nil)
+	} // END for
+	Aggregate := p_HTML.aggColumns[p_Column]
+	if Aggregate == nil {
+		Definition := p_Tag.Aggregate
+		if Definition == "" {
+			Definition = p_HTML.aggDefs[p_Name]
+		} // END if
+		if Definition == "" {
+			return
+		} // END if
+		Aggregate = parseAggregate(Definition,p_Tag.Format)
+		Aggregate.style = p_Style
+		p_HTML.aggColumns[p_Column] = Aggregate
+	} // END if
+	Aggregate.add(p_Value)
+	p_HTML.groupAggregate(p_Column,Aggregate,p_Value)
+} // END aggregateValue
+
+// Create an aggregate from a definition like "avg:%.1f", p_Format is the format of the column
+func parseAggregate(p_Definition, p_Format string) *t_Aggregate {
+	Kind,Format,_ := strings.Cut(p_Definition,":")
+	Kind = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(Kind)),"-","")
+	switch Kind {
+		case GC_AggSum, GC_AggAvg, GC_AggMin, GC_AggMax, GC_AggCount: // OK
+		case GC_AggDistinct, "countdistinct": Kind = GC_AggDistinct
+		default: panic(fmt.Sprintf("Unknown aggregate function: %s",p_Definition))
+	} // END switch
+	if Format == "" {
+		Format = p_Format
+	} // END if
+	Aggregate := &t_Aggregate{kind: Kind, format: Format}
+	if Kind == GC_AggDistinct {
+		Aggregate.distinct = make(map[string]bool)
+	} // END if
+	return Aggregate
+} // END parseAggregate
+
+// Return a new aggregate with the definition of this one, e.g. for the subtotals of a group
+func (p_Aggregate *t_Aggregate) empty() *t_Aggregate {
+	Aggregate := &t_Aggregate{kind: p_Aggregate.kind, format: p_Aggregate.format, style: p_Aggregate.style}
+	if Aggregate.kind == GC_AggDistinct {
+		Aggregate.distinct = make(map[string]bool)
+	} // END if
+	return Aggregate
+} // END empty
+
+// Add a value, nil values are ignored
+func (p_Aggregate *t_Aggregate) add(p_Value reflect.Value) {
+	Value := derefValue(p_Value)
+	if !Value.IsValid() {
+		return
+	} // END if
+	p_Aggregate.count++
+	switch p_Aggregate.kind {
+		case GC_AggCount: return
+		case GC_AggDistinct: {
+			if Bytes,ok := Value.Interface().([]byte); ok {
+				p_Aggregate.distinct[string(Bytes)] = true
+			} else {
+				p_Aggregate.distinct[fmt.Sprint(Value.Interface())] = true
+			} // END if
+			return
+		} // END case
+	} // END switch
+	Number,ok := numericValue(Value)
+	if !ok {
+		return
+	} // END if
+	if p_Aggregate.numbers == 0 || Number < p_Aggregate.min {
+		p_Aggregate.min = Number
+	} // END if
+	if p_Aggregate.numbers == 0 || Number > p_Aggregate.max {
+		p_Aggregate.max = Number
+	} // END if
+	p_Aggregate.sum += Number
+	p_Aggregate.numbers++
+} // END add
+
+// The formatted result of the aggregate; without numeric values sum is 0, avg, min and max are "&nbsp;"
+func (p_Aggregate *t_Aggregate) result() string {
+	var Number float64
+	switch p_Aggregate.kind {
+		case GC_AggCount: return strconv.Itoa(p_Aggregate.count)
+		case GC_AggDistinct: return strconv.Itoa(len(p_Aggregate.distinct))
+		case GC_AggSum: Number = p_Aggregate.sum
+		case GC_AggMin: Number = p_Aggregate.min
+		case GC_AggMax: Number = p_Aggregate.max
+		case GC_AggAvg: {
+			if p_Aggregate.numbers > 0 {
+				Number = p_Aggregate.sum / float64(p_Aggregate.numbers)
+			} // END if
+		} // END case
+	} // END switch
+	if p_Aggregate.numbers == 0 && p_Aggregate.kind != GC_AggSum {
+		return "&nbsp;"
+	} // END if
+	return formatNumber(Number,p_Aggregate.format)
+} // END result
+
+// Return the number of a numeric value: integer and float kinds, strings and []byte
+// containing a number, as returned by database/sql for NUMERIC and DECIMAL columns.
+func numericValue(p_Value reflect.Value) (float64, bool) {
+	switch p_Value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64: return float64(p_Value.Int()),true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64: return float64(p_Value.Uint()),true
+		case reflect.Float32, reflect.Float64: return p_Value.Float(),true
+		case reflect.String: return parseNumber(p_Value.String())
+		case reflect.Slice: {
+			if p_Value.Type().Elem().Kind() == reflect.Uint8 {
+				return parseNumber(string(p_Value.Bytes()))
+			} // END if
+		} // END case
+	} // END switch
+	return 0,false
+} // END numericValue
+
+func parseNumber(p_Text string) (float64, bool) {
+	Number,err := strconv.ParseFloat(strings.TrimSpace(p_Text),64)
+	return Number,err == nil
+} // END parseNumber
+
+// Format a number with p_Format: integer verbs get whole numbers, other numbers are printed
+// with two decimals; %s and %v get the number as text.
+func formatNumber(p_Number float64, p_Format string) string {
+	Whole := p_Number == math.Trunc(p_Number) && math.Abs(p_Number) < 1e15
+	Text := strconv.FormatFloat(p_Number,'f',2,64)
+	if Whole {
+		Text = strconv.FormatInt(int64(p_Number),10)
+	} // END if
+	match := regExp_FormatVerb.FindStringSubmatch(p_Format)
+	if match == nil {
+		return Text
+	} // END if
+	switch match[2] {
+		case "d", "b", "o", "x", "X", "c": {
+			if Whole {
+				return fmt.Sprintf(p_Format,int64(p_Number))
+			} // END if
+			return Text
+		} // END case
+		case "s", "v", "q": return fmt.Sprintf(p_Format,Text)
+		default: return fmt.Sprintf(p_Format,p_Number)
+	} // END switch
+} // END formatNumber
@@ -0,0 +1,161 @@
+package UTL_HTML
+//
+// UTL_HTML_Auth
+// Version: $Id$
+//
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// *************************************************************************************
+// Basic authentication: RequireAuth checks the credentials of every request with a
+// verifier, answers missing or wrong credentials with a WWW-Authenticate challenge and
+// a 401 page built with UTL_HTML, and passes the authenticated user on in the context
+// of the request. Built-in verifiers are an in-memory user map and an htpasswd-style
+// file with salted SHA-256 hashes:
+//
+//  fred:{SSHA256}base64(SHA-256(password + salt) + salt)
+//
+// Lines with # are comments, HtpasswdLine creates the lines.
+
+const (
+	GC_AuthTitle   string = "401 Unauthorized"
+	GC_AuthMessage string = "Please sign in to access this page."
+) // END const
+
+// Checks the password of a user; implementations compare in constant time
+type T_Verifier interface {
+	Verify(p_User, p_Password string) bool
+} // END T_Verifier
+
+// A function as T_Verifier, e.g. for a lookup in a database
+type T_VerifierFunc func(p_User, p_Password string) bool
+
+func (p_Func T_VerifierFunc) Verify(p_User, p_Password string) bool { return p_Func(p_User,p_Password) }
+
+// Users with their passwords in plain text, for tests and small tools
+type T_UserMap map[string]string
+
+// Compare the password in constant time; unknown users take the same time
+func (p_Users T_UserMap) Verify(p_User, p_Password string) bool {
+	Password,ok := p_Users[p_User]
+	Expected, Given := sha256.Sum256([]byte(Password)), sha256.Sum256([]byte(p_Password)) // same length for ConstantTimeCompare
+	return subtle.ConstantTimeCompare(Expected[:],Given[:]) == 1 && ok
+} // END Verify
+
+// Users of an htpasswd-style file with salted SHA-256 hashes
+type T_Htpasswd struct {
+	users map[string][]byte // user → hash + salt
+} // END T_Htpasswd
+
+// Read an htpasswd-style file, see HtpasswdLine
+func LoadHtpasswd(p_File string) (*T_Htpasswd, error) {
+	File,err := os.Open(p_File)
+	if err != nil {
+		return nil, err
+	} // END if
+	defer File.Close()
+
+	Htpasswd := &T_Htpasswd{users: make(map[string][]byte)}
+	Scanner := bufio.NewScanner(File)
+	for Line := 1; Scanner.Scan(); Line++ {
+		Text := strings.TrimSpace(Scanner.Text())
+		if Text == "" || strings.HasPrefix(Text,"#") { continue }
+		User,Hash,ok := strings.Cut(Text,":")
+		Encoded,Salted := strings.CutPrefix(Hash,"{SSHA256}")
+		Decoded,err := base64.StdEncoding.DecodeString(Encoded)
+		if !ok || !Salted || err != nil || len(Decoded) <= sha256.Size {
+			return nil, fmt.Errorf("%s:%d: not a salted SHA-256 entry",p_File,Line)
+		} // END if
+		Htpasswd.users[User] = Decoded
+	} // END for
+	if err := Scanner.Err(); err != nil {
+		return nil, err
+	} // END if
+	return Htpasswd, nil
+} // END LoadHtpasswd
+
+// Hash the password with the salt of the entry and compare in constant time; unknown users take the same time
+func (p_Htpasswd *T_Htpasswd) Verify(p_User, p_Password string) bool {
+	Entry,ok := p_Htpasswd.users[p_User]
+	if !ok {
+		Entry = v_AuthDummy
+	} // END if
+	Hash := authHash(p_Password,Entry[sha256.Size:])
+	return subtle.ConstantTimeCompare(Hash,Entry[:sha256.Size]) == 1 && ok
+} // END Verify
+
+// Return a line of an htpasswd-style file for the user, with a random salt
+func HtpasswdLine(p_User, p_Password string) string {
+	Salt := make([]byte,16)
+	rand.Read(Salt)
+	return p_User + ":{SSHA256}" + base64.StdEncoding.EncodeToString(append(authHash(p_Password,Salt),Salt...))
+} // END HtpasswdLine
+
+// Wrap a handler: requests without valid Basic credentials are answered with 401 Unauthorized, a
+// WWW-Authenticate challenge for the realm and a page with GC_AuthTitle and GC_AuthMessage. The user
+// is passed on in the context of the request, see AuthUser and T_HTML.User.
+func RequireAuth(p_Realm string, p_Verifier T_Verifier, p_Next http.Handler) http.Handler {
+	Challenge := fmt.Sprintf(`Basic realm=%q, charset="UTF-8"`,p_Realm)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		User,Password,ok := r.BasicAuth()
+		if !ok || !p_Verifier.Verify(User,Password) {
+			w.Header().Set("WWW-Authenticate",Challenge)
+			w.Header().Set("Content-Type","text/html; charset=utf-8")
+			w.WriteHeader(http.StatusUnauthorized)
+			New(GC_DocTypeHTML5,0x02).
+				HtmlOpen().
+					HeadOpen().
+						Meta("charset","utf-8").
+						Title(GC_AuthTitle).
+					TagCloseUntil("head").
+					BodyOpen().
+						Header("1",GC_AuthTitle).
+						P(GC_AuthMessage).
+				CloseTagsAndWrite(w)
+			return
+		} // END if
+		p_Next.ServeHTTP(w,r.WithContext(context.WithValue(r.Context(),t_AuthKey{},User)))
+	})
+} // END RequireAuth
+
+// The user authenticated by RequireAuth, "" if the request has not been authenticated
+func AuthUser(r *http.Request) string {
+	User,_ := r.Context().Value(t_AuthKey{}).(string)
+	return User
+} // END AuthUser
+
+// The user authenticated by RequireAuth for documents with WithContext(r.Context()), "" if there is none
+func (p_HTML *T_HTML) User() string {
+	if p_HTML.ctx == nil {
+		return ""
+	} // END if
+	User,_ := p_HTML.ctx.Value(t_AuthKey{}).(string)
+	return User
+} // END User
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// Key of the user in the context of a request, see RequireAuth
+type t_AuthKey struct{}
+
+// Entry for unknown users: they take as long as known users
+var v_AuthDummy = append(authHash("",make([]byte,16)),make([]byte,16)...)
+
+// SHA-256 of the password and the salt
+func authHash(p_Password string, p_Salt []byte) []byte {
+	Hash := sha256.New()
+	Hash.Write([]byte(p_Password))
+	Hash.Write(p_Salt)
+	return Hash.Sum(nil)
+} // END authHash
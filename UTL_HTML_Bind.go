@@ -0,0 +1,230 @@
+package UTL_HTML
+//
+// UTL_HTML_Bind
+// Version: $Id$
+//
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"reflect"
+	"time"
+	"net/url"
+	"net/http"
+	"database/sql"
+	"encoding"
+)
+
+// *************************************************************************************
+// Binding request parameters: the counterpart of FormStruct. The URL and form values of a
+// request are decoded into a struct, field by field, by the same column names that
+// TrThStruct and FormFields use: the name of the field, "Address.City" for nested structs,
+// or the Param of the html struct-tag. Skip fields are left alone.
+//
+//  string                            → the value as sent, spaces included
+//  ints, uints, floats               → strconv without surrounding spaces, an empty value is the zero value
+//  bool                              → checkbox: true for on, true, yes, 1; false if the parameter is missing
+//  time.Time                         → Layout of the struct-tag, the formats of date, datetime-local and time inputs, RFC 3339
+//  []T                               → one element per value of a multi-value parameter
+//  *T                                → nil if the parameter is missing or empty
+//  sql.Scanner, TextUnmarshaler      → sql.NullString & Co. and types that decode themselves
+//
+// Parameters that are missing leave the field unchanged, except for bool fields; a missing bool
+// field inside a nil *struct is left alone rather than allocating the struct.
+
+// Errors by name of the request parameter, e.g. "Quantity": `"three" is not a whole number`
+type T_FieldErrors map[string]string
+
+// Return the errors as one text, sorted by the names of the parameters
+func (p_Errors T_FieldErrors) Error() string {
+	Names := make([]string,0,len(p_Errors))
+	for Name := range p_Errors {
+		Names = append(Names,Name)
+	} // END for
+	sort.Strings(Names)
+	for index,Name := range Names {
+		Names[index] = Name + ": " + p_Errors[Name]
+	} // END for
+	return strings.Join(Names,"; ")
+} // END Error
+
+// Decode the URL and form values of a request into the struct p_Target points to, see BindParameters.
+// Multipart requests are parsed too, see ReadUploads. Errors of parsing the request are returned with the name "".
+func BindRequest(r *http.Request, p_Target any) T_FieldErrors {
+	if err := parseForm(r); err != nil {
+		return T_FieldErrors{"": err.Error()}
+	} // END if
+	return BindParameters(r.Form,p_Target)
+} // END BindRequest
+
+// Decode values into the struct p_Target points to and return the conversion errors by parameter name, nil if there are none.
+// Fields with errors keep their value.
+func BindParameters(p_Values url.Values, p_Target any) T_FieldErrors {
+	Target := reflect.ValueOf(p_Target)
+	if Target.Kind() != reflect.Ptr || Target.IsNil() || Target.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("Unknown datatype used in BindParameters: %T",p_Target))
+	} // END if
+	Struct := Target.Elem()
+
+	var Errors T_FieldErrors
+	for _,Column := range structMeta(Struct.Type()).Columns {
+		Name := Column.param()
+		Values,Present := p_Values[Name]
+		if !Present && (Column.Type.Kind() != reflect.Bool || !fieldByIndex(Struct,Column.Index).IsValid()) {
+			continue // missing parameters leave the field unchanged, a nil *struct stays nil
+		} // END if
+		if err := bindValue(settableField(Struct,Column.Index),Values,Column.Tag); err != nil {
+			if Errors == nil {
+				Errors = make(T_FieldErrors)
+			} // END if
+			Errors[Name] = err.Error()
+		} // END if
+	} // END for
+	return Errors
+} // END BindParameters
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+var v_ScannerType = reflect.TypeFor[sql.Scanner]()
+var v_TextUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// Layouts of time.Time values: date, datetime-local and time inputs, RFC 3339
+var v_BindTimeLayouts = []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02T15:04:05", time.RFC3339, "2006-01-02 15:04:05", "15:04", "15:04:05"}
+
+// Like fieldByIndex, but nil pointers to embedded and nested structs are allocated
+func settableField(p_Struct reflect.Value, p_Index []int) reflect.Value {
+	Value := p_Struct
+	for position,index := range p_Index {
+		if position > 0 && Value.Kind() == reflect.Ptr {
+			if Value.IsNil() {
+				Value.Set(reflect.New(Value.Type().Elem()))
+			} // END if
+			Value = Value.Elem()
+		} // END if
+		Value = Value.Field(index)
+	} // END for
+	return Value
+} // END settableField
+
+// The type without pointers
+func derefType(p_Type reflect.Type) reflect.Type {
+	for p_Type.Kind() == reflect.Ptr {
+		p_Type = p_Type.Elem()
+	} // END for
+	return p_Type
+} // END derefType
+
+// The value is empty for the type: strings only without any character, other types without anything but spaces
+func bindEmpty(p_Type reflect.Type, p_Text string) bool {
+	if derefType(p_Type).Kind() == reflect.String {
+		return p_Text == ""
+	} // END if
+	return strings.TrimSpace(p_Text) == ""
+} // END bindEmpty
+
+// Set a field from the values of its parameter; strings keep their spaces, numbers, bools and times are trimmed
+func bindValue(p_Field reflect.Value, p_Values []string, p_Tag t_HtmlTag) error {
+	Text := ""
+	if len(p_Values) > 0 {
+		Text = p_Values[0]
+	} // END if
+	Trimmed := strings.TrimSpace(Text)
+
+	// types that decode themselves: sql.NullString & Co., TextUnmarshaler
+	if Pointer := p_Field.Addr(); Pointer.Type().Implements(v_ScannerType) {
+		var Source any
+		if Trimmed != "" {
+			Source = Text
+		} // END if
+		return Pointer.Interface().(sql.Scanner).Scan(Source)
+	} // END if
+	if Pointer := p_Field.Addr(); Pointer.Type().Implements(v_TextUnmarshalerType) && p_Field.Type() != v_TimeType {
+		return Pointer.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(Text))
+	} // END if
+
+	switch p_Field.Kind() {
+		case reflect.Ptr: {
+			if bindEmpty(p_Field.Type(),Text) {
+				p_Field.SetZero() // optional field without value
+				return nil
+			} // END if
+			Value := reflect.New(p_Field.Type().Elem())
+			if err := bindValue(Value.Elem(),p_Values,p_Tag); err != nil {
+				return err
+			} // END if
+			p_Field.Set(Value)
+			return nil
+		} // END case
+		case reflect.Slice: {
+			if p_Field.Type().Elem().Kind() == reflect.Uint8 {
+				p_Field.SetBytes([]byte(Text)) // []byte is one value
+				return nil
+			} // END if
+			Slice := reflect.MakeSlice(p_Field.Type(),0,len(p_Values))
+			for _,Value := range p_Values {
+				if bindEmpty(p_Field.Type().Elem(),Value) { continue }
+				Element := reflect.New(p_Field.Type().Elem()).Elem()
+				if err := bindValue(Element,[]string{Value},p_Tag); err != nil {
+					return err
+				} // END if
+				Slice = reflect.Append(Slice,Element)
+			} // END for
+			p_Field.Set(Slice)
+			return nil
+		} // END case
+		case reflect.Bool: {
+			switch strings.ToLower(Trimmed) {
+				case "on", "true", "yes", "1": p_Field.SetBool(true)
+				case "", "off", "false", "no", "0": p_Field.SetBool(false)
+				default: return fmt.Errorf("%q is not a yes/no value",Trimmed)
+			} // END switch
+			return nil
+		} // END case
+	} // END switch
+
+	if bindEmpty(p_Field.Type(),Text) {
+		p_Field.SetZero()
+		return nil
+	} // END if
+	if p_Field.Type() == v_TimeType {
+		Layouts := v_BindTimeLayouts
+		if p_Tag.Layout != "" {
+			Layouts = append([]string{p_Tag.Layout},Layouts...)
+		} // END if
+		for _,Layout := range Layouts {
+			if Time,err := time.ParseInLocation(Layout,Trimmed,time.Local); err == nil {
+				p_Field.Set(reflect.ValueOf(Time))
+				return nil
+			} // END if
+		} // END for
+		return fmt.Errorf("%q is not a date or time",Trimmed)
+	} // END if
+	switch p_Field.Kind() {
+		case reflect.String: p_Field.SetString(Text)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64: {
+			Number,err := strconv.ParseInt(Trimmed,10,p_Field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("%q is not a whole number",Trimmed)
+			} // END if
+			p_Field.SetInt(Number)
+		} // END case
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64: {
+			Number,err := strconv.ParseUint(Trimmed,10,p_Field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("%q is not a positive whole number",Trimmed)
+			} // END if
+			p_Field.SetUint(Number)
+		} // END case
+		case reflect.Float32, reflect.Float64: {
+			Number,err := strconv.ParseFloat(Trimmed,p_Field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("%q is not a number",Trimmed)
+			} // END if
+			p_Field.SetFloat(Number)
+		} // END case
+		default: return fmt.Errorf("fields of type %s can not be bound",p_Field.Type())
+	} // END switch
+	return nil
+} // END bindValue
@@ -0,0 +1,242 @@
+package UTL_HTML
+//
+// UTL_HTML_Csrf
+// Version: $Id$
+//
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// *************************************************************************************
+// Protection against cross-site request forgery: once SetCSRF has configured a server
+// secret, FormOpen adds a signed, time-limited token as hidden field to every POST form.
+// The token is bound to the session of the user. VerifyCSRF checks the token and the
+// Origin and Sec-Fetch-Site headers of a request, CSRFProtect does it for all requests
+// of a handler, so the handlers of the forms don't have to.
+//
+//  token = <time of issue, base 36>.<HMAC-SHA256(secret, session + time), base64url>
+
+const (
+	GC_CSRFField  string        = "csrf_token"    // name of the hidden field
+	GC_CSRFHeader string        = "X-CSRF-Token"  // the token of fetch and htmx requests, which have no form field
+	GC_CSRFMaxAge time.Duration = 12 * time.Hour  // lifetime of a token if T_CSRF.MaxAge is 0
+) // END const
+
+// Configuration of the CSRF protection
+type T_CSRF struct {
+	Secret  []byte                       // server secret, at least 32 random bytes; nil: no protection
+	MaxAge  time.Duration                // lifetime of a token, 0: GC_CSRFMaxAge
+	Session func(r *http.Request) string // session identifier of a request, e.g. the value of the session cookie; required with a secret
+	Origins []string                     // trusted origins besides the host of the request, e.g. "https://shop.example.org"
+} // END T_CSRF
+
+// Set the configuration of the CSRF protection for the whole program, usually once at the start.
+// A secret needs the Session function: tokens that are not bound to a session would be valid for every user.
+func SetCSRF(p_Config T_CSRF) {
+	if p_Config.Secret != nil && len(p_Config.Secret) < 32 {
+		panic("SetCSRF: the secret must have at least 32 bytes")
+	} // END if
+	if p_Config.Secret != nil && p_Config.Session == nil {
+		panic("SetCSRF: the secret needs a Session function")
+	} // END if
+	if p_Config.MaxAge <= 0 {
+		p_Config.MaxAge = GC_CSRFMaxAge
+	} // END if
+	v_CSRF.Store(&p_Config)
+} // END SetCSRF
+
+// Return a token for the session, e.g. for the hx-headers of htmx requests; "" if no secret is configured
+func CSRFToken(p_Session string) string {
+	Config := v_CSRF.Load()
+	if Config == nil || Config.Secret == nil {
+		return ""
+	} // END if
+	return csrfToken(Config,p_Session,time.Now())
+} // END CSRFToken
+
+// Set the session identifier for the tokens of the POST forms of the document.
+// Not needed for documents with WithContext(r.Context()) in handlers wrapped by CSRFProtect.
+func (p_HTML *T_HTML) CSRF(p_Session string) *T_HTML {
+	p_HTML.csrfSession = &p_Session
+	return p_HTML
+} // END CSRF
+
+// Check a request: requests with the methods GET, HEAD, OPTIONS and TRACE are accepted, all
+// others need the headers Origin and Sec-Fetch-Site of the same origin - if the browser sends
+// them - and a valid token of the session in the form field GC_CSRFField or the header GC_CSRFHeader.
+// A multipart/form-data body is not parsed: the token is taken from the fields in its first 8 KB, where
+// FormOpen puts it, and the body is left for ReadUploads and SaveUploads with their limits.
+func VerifyCSRF(r *http.Request) error {
+	switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace: return nil
+	} // END switch
+	Config := v_CSRF.Load()
+	if Config == nil || Config.Secret == nil {
+		return nil
+	} // END if
+	if err := csrfOrigin(Config,r); err != nil {
+		return err
+	} // END if
+	Token := r.Header.Get(GC_CSRFHeader)
+	if MediaType,Params,_ := mime.ParseMediaType(r.Header.Get("Content-Type")); Token == "" && MediaType == "multipart/form-data" {
+		Token = csrfMultipart(r,Params["boundary"])
+	} else if Token == "" {
+		Token = r.PostFormValue(GC_CSRFField)
+	} // END if
+	return csrfVerify(Config,csrfSession(Config,r),Token,time.Now())
+} // END VerifyCSRF
+
+// Wrap a handler: requests failing VerifyCSRF are answered with 403 Forbidden. The session
+// identifier is passed on in the context of the request, so documents with WithContext(r.Context())
+// add the tokens to their POST forms without calling CSRF().
+func CSRFProtect(p_Next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := VerifyCSRF(r); err != nil {
+			http.Error(w,err.Error(),http.StatusForbidden)
+			return
+		} // END if
+		if Config := v_CSRF.Load(); Config != nil && Config.Secret != nil {
+			r = r.WithContext(context.WithValue(r.Context(),t_CSRFKey{},csrfSession(Config,r)))
+		} // END if
+		p_Next.ServeHTTP(w,r)
+	})
+} // END CSRFProtect
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+var v_CSRF atomic.Pointer[T_CSRF] // configuration of SetCSRF
+
+// Key of the session identifier in the context of a request, see CSRFProtect
+type t_CSRFKey struct{}
+
+var v_CSRFMissing = errors.New("CSRF token missing")
+var v_CSRFInvalid = errors.New("CSRF token invalid")
+var v_CSRFExpired = errors.New("CSRF token expired")
+var v_CSRFSession = errors.New("FormOpen: no session for the CSRF token, see CSRF and CSRFProtect")
+
+// Append the hidden field with the token to a POST form, called by FormOpen.
+// Without the session of CSRF() or CSRFProtect the form gets no token and Err() returns v_CSRFSession.
+func (p_HTML *T_HTML) csrfField(p_Method string) {
+	Config := v_CSRF.Load()
+	if Config == nil || Config.Secret == nil || !strings.EqualFold(p_Method,http.MethodPost) {
+		return
+	} // END if
+	Session, ok := "", false
+	if p_HTML.csrfSession != nil {
+		Session, ok = *p_HTML.csrfSession, true
+	} else if p_HTML.ctx != nil {
+		Session,ok = p_HTML.ctx.Value(t_CSRFKey{}).(string)
+	} // END if
+	if !ok {
+		p_HTML.setError(v_CSRFSession) // a token of another session would be refused anyway
+		return
+	} // END if
+	p_HTML.HiddenField(GC_CSRFField,csrfToken(Config,Session,time.Now()))
+} // END csrfField
+
+// Size of the start of a multipart/form-data body that is searched for the token, see csrfMultipart
+const gc_CSRFHead int = 8 << 10
+
+// Return the token of a multipart/form-data body from the form fields within its first gc_CSRFHead bytes.
+// The bytes read are put back in front of r.Body, the rest of the body is not touched.
+func csrfMultipart(r *http.Request, p_Boundary string) string {
+	if r.Body == nil || p_Boundary == "" {
+		return ""
+	} // END if
+	Head,_ := io.ReadAll(io.LimitReader(r.Body,int64(gc_CSRFHead)))
+	r.Body = struct{ io.Reader; io.Closer }{io.MultiReader(bytes.NewReader(Head),r.Body),r.Body}
+	Reader := multipart.NewReader(bytes.NewReader(Head),p_Boundary)
+	for {
+		Part,err := Reader.NextPart()
+		if err != nil || Part.FileName() != "" {
+			return "" // the end of the head or the first file
+		} // END if
+		if Part.FormName() == GC_CSRFField {
+			Token,err := io.ReadAll(Part)
+			if err != nil {
+				return "" // cut off by the end of the head
+			} // END if
+			return string(Token)
+		} // END if
+	} // END for
+} // END csrfMultipart
+
+// Session identifier of a request
+func csrfSession(p_Config *T_CSRF, r *http.Request) string {
+	return p_Config.Session(r)
+} // END csrfSession
+
+// Token of a session issued at a time
+func csrfToken(p_Config *T_CSRF, p_Session string, p_Time time.Time) string {
+	Issued := strconv.FormatInt(p_Time.Unix(),36)
+	return Issued + "." + base64.RawURLEncoding.EncodeToString(csrfMAC(p_Config,p_Session,Issued))
+} // END csrfToken
+
+// Signature of the session and the time of issue
+func csrfMAC(p_Config *T_CSRF, p_Session, p_Issued string) []byte {
+	MAC := hmac.New(sha256.New,p_Config.Secret)
+	MAC.Write([]byte(p_Session))
+	MAC.Write([]byte{0})
+	MAC.Write([]byte(p_Issued))
+	return MAC.Sum(nil)
+} // END csrfMAC
+
+// Check the signature and the age of a token
+func csrfVerify(p_Config *T_CSRF, p_Session, p_Token string, p_Now time.Time) error {
+	if p_Token == "" {
+		return v_CSRFMissing
+	} // END if
+	Issued,Signature,ok := strings.Cut(p_Token,".")
+	if !ok {
+		return v_CSRFInvalid
+	} // END if
+	MAC,err := base64.RawURLEncoding.DecodeString(Signature)
+	if err != nil || !hmac.Equal(MAC,csrfMAC(p_Config,p_Session,Issued)) {
+		return v_CSRFInvalid
+	} // END if
+	Seconds,err := strconv.ParseInt(Issued,36,64)
+	if err != nil {
+		return v_CSRFInvalid
+	} // END if
+	if Age := p_Now.Sub(time.Unix(Seconds,0)); Age > p_Config.MaxAge || Age < -time.Minute {
+		return v_CSRFExpired
+	} // END if
+	return nil
+} // END csrfVerify
+
+// Check the headers Sec-Fetch-Site and Origin: the request must come from the own host or a trusted origin
+func csrfOrigin(p_Config *T_CSRF, r *http.Request) error {
+	switch Site := r.Header.Get("Sec-Fetch-Site"); Site {
+		case "", "same-origin", "none": // no header, the own site, typed by the user
+		default: {
+			if !slices.Contains(p_Config.Origins,r.Header.Get("Origin")) {
+				return fmt.Errorf("cross-site request refused (Sec-Fetch-Site: %s)",Site)
+			} // END if
+		} // END default
+	} // END switch
+	Origin := r.Header.Get("Origin")
+	if Origin == "" || slices.Contains(p_Config.Origins,Origin) {
+		return nil
+	} // END if
+	if URL,err := url.Parse(Origin); err == nil && URL.Host != "" && strings.EqualFold(URL.Host,r.Host) {
+		return nil
+	} // END if
+	return fmt.Errorf("cross-site request refused (Origin: %s)",Origin)
+} // END csrfOrigin
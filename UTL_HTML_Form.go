@@ -4,9 +4,8 @@ package UTL_HTML
 // Version: $Id: UTL_HTML_Form.go 82 2025-05-02 19:59:27Z fjuedes $
 //
 import (
-	"fmt"
-	"reflect"
 	"slices"
+	"strings"
 )
 
 func (p_HTML *T_HTML) FormOpen(p_Action, p_Method string, p_Attributes ...string) *T_HTML {
@@ -14,35 +13,27 @@ func (p_HTML *T_HTML) FormOpen(p_Action, p_Method string, p_Attributes ...string
 	appendAttribute("action",p_Action,&Arguments)
 	appendAttribute("method",p_Method,&Arguments)
 	Arguments = append(Arguments, p_Attributes...)
-	return p_HTML.TagOpen("form", Arguments...)
+	p_HTML.formOffset = p_HTML.content.Len() + len("<form")
+	if slices.Contains(p_Attributes,"enctype") {
+		p_HTML.formOffset = 0
+	} // END if
+	p_HTML.TagOpen("form", Arguments...)
+	p_HTML.csrfField(p_Method) // POST forms get the CSRF token, see SetCSRF
+	return p_HTML
 } // END OpenForm
 
 func (p_HTML *T_HTML) HiddenField(p_name, p_value string, p_Attributes... string ) *T_HTML {
 	Arguments := []string{"type", "hidden", "name", p_name, "value", p_value}
 	Arguments = append(Arguments, p_Attributes...)
-	return p_HTML.Tag("input", "", Arguments...)
+	return p_HTML.Tag("input", "", Arguments...).formMessage(p_name) // a hidden field has no error attributes, but the message is shown
 } // END HiddenField
 
-func (p_HTML *T_HTML) TextField(p_name, p_size, p_maxlen, p_value string, p_Attributes ...string) *T_HTML {
-  Arguments := make([]string,0,len(p_Attributes)+4)
-	appendAttribute("type","text",&Arguments)
-	appendAttribute("name",p_name,&Arguments)
-	appendAttribute("size",p_size,&Arguments)
-	appendAttribute("maxlen",p_maxlen,&Arguments)
-	appendAttribute("value",p_value,&Arguments)
-	Arguments = append(Arguments, p_Attributes...)
-	return p_HTML.Tag("input", "", Arguments...)
+func (p_HTML *T_HTML) TextField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("text",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes))
 } // END TextField
 
-func TextField(p_name, p_size, p_maxlen, p_value string, p_Attributes ...string) string {
-  Arguments := make([]string,0,len(p_Attributes)+4)
-	appendAttribute("type","text",&Arguments)
-	appendAttribute("name",p_name,&Arguments)
-	appendAttribute("size",p_size,&Arguments)
-	appendAttribute("maxlen",p_maxlen,&Arguments)
-	appendAttribute("value",p_value,&Arguments)
-	Arguments = append(Arguments, p_Attributes...)
-	return Tag("input", "", Arguments...)
+func TextField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) string {
+	return Tag("input", "", inputArguments("text",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes)...)
 } // END TextField
 
 func (p_HTML *T_HTML) BoolField(p_name string, p_checked bool, p_Attributes ...string) *T_HTML {
@@ -53,7 +44,7 @@ func (p_HTML *T_HTML) BoolField(p_name string, p_checked bool, p_Attributes ...s
 		Arguments = append(Arguments,"checked","checked")
 	} // END if
 	Arguments = append(Arguments, p_Attributes...)
-	return p_HTML.Tag("input", "", Arguments...)
+	return p_HTML.Tag("input", "", p_HTML.formInvalid(p_name,Arguments)...).formMessage(p_name)
 } // END BoolField
 
 func BoolField(p_name string, p_checked bool, p_Attributes ...string) string {
@@ -67,37 +58,6 @@ func BoolField(p_name string, p_checked bool, p_Attributes ...string) string {
 	return Tag("input", "", Arguments...)
 } // END BoolField
 
-// Append a select menu, with the menu-items from a map[string]string
-func (p_HTML *T_HTML) SelectMenu(p_FieldName, p_MenuClassName, p_ItemClassName, p_DefaultValue string, p_CompareFunc t_CompareFunc, p_MenuItems any, p_Attributes ...any) *T_HTML {
-	DataItems := reflect.ValueOf(p_MenuItems)
-	if DataItems.Kind() != reflect.Map { panic(fmt.Sprintf("Unknown datatype used in TrTdMapSimple: %s",DataItems)) }
-
-	Arguments := make([]string,0,len(p_Attributes)+2)
-	appendAttribute("class",p_MenuClassName,&Arguments)
-	appendAttribute("name",p_FieldName,&Arguments)
-	p_HTML.TagOpen("select",Arguments...)
-
-	Keys := DataItems.MapKeys()
-	if p_CompareFunc != nil {
-		slices.SortFunc(Keys,p_CompareFunc)
-	} // END if
-	for _,Key := range Keys {
-		Value := ""
-		if DataItems.MapIndex(Key).Kind() == reflect.Ptr {
-			Value = fmt.Sprint(DataItems.MapIndex(Key).Elem())
-		} else {
-			Value = fmt.Sprint(DataItems.MapIndex(Key))
-		} // END if
-		if Value == p_DefaultValue {
-		  p_HTML.Tag("option",fmt.Sprint(Key),"value",Value,"class","selected","selected",p_ItemClassName)
-		} else {
-		  p_HTML.Tag("option",fmt.Sprint(Key),"value",Value,"class",p_ItemClassName)
-		} // END if
-  } // END for
-	return p_HTML
-} // END SelectMenu
-
-
 func (p_HTML *T_HTML) SubmitButton(p_name, p_label, p_value string, p_Attributes ...string) *T_HTML {
   Arguments := make([]string,0,len(p_Attributes)+3)
 	appendAttribute("type","submit",&Arguments)
@@ -116,3 +76,324 @@ func SubmitButton(p_name, p_label, p_value, p_Class string, p_Attributes ...stri
 	return Tag("button", p_label, Arguments...)
 } // END Button
 
+
+// -------------------------------------------------------------------
+// Inputs of the other HTML5 types. They share the attribute handling of TextField: empty
+// parameters are left out, p_Attributes are appended, and the method versions show the
+// errors of FormErrors. Values are inserted as they are, like the values of TextField.
+
+// Append an input of any type
+func (p_HTML *T_HTML) InputField(p_Type, p_name, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments(p_Type,p_name,[]string{"value",p_value},p_Attributes))
+} // END InputField
+
+func InputField(p_Type, p_name, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments(p_Type,p_name,[]string{"value",p_value},p_Attributes)...)
+} // END InputField
+
+// Append an input for an email address, like TextField
+func (p_HTML *T_HTML) EmailField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("email",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes))
+} // END EmailField
+
+func EmailField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("email",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes)...)
+} // END EmailField
+
+// Append an input for a URL, like TextField
+func (p_HTML *T_HTML) URLField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("url",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes))
+} // END URLField
+
+func URLField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("url",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes)...)
+} // END URLField
+
+// Append an input for a telephone number, like TextField
+func (p_HTML *T_HTML) TelField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("tel",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes))
+} // END TelField
+
+func TelField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("tel",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes)...)
+} // END TelField
+
+// Append an input for a password, like TextField; usually without value
+func (p_HTML *T_HTML) PasswordField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("password",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes))
+} // END PasswordField
+
+func PasswordField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("password",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes)...)
+} // END PasswordField
+
+// Append a search input, like TextField
+func (p_HTML *T_HTML) SearchField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("search",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes))
+} // END SearchField
+
+func SearchField(p_name, p_size, p_maxlength, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("search",p_name,[]string{"size",p_size,"maxlength",p_maxlength,"value",p_value},p_Attributes)...)
+} // END SearchField
+
+// Append a number input with range and step, step "any" for decimals
+func (p_HTML *T_HTML) NumberField(p_name, p_min, p_max, p_step, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("number",p_name,[]string{"min",p_min,"max",p_max,"step",p_step,"value",p_value},p_Attributes))
+} // END NumberField
+
+func NumberField(p_name, p_min, p_max, p_step, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("number",p_name,[]string{"min",p_min,"max",p_max,"step",p_step,"value",p_value},p_Attributes)...)
+} // END NumberField
+
+// Append a slider, like NumberField
+func (p_HTML *T_HTML) RangeField(p_name, p_min, p_max, p_step, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("range",p_name,[]string{"min",p_min,"max",p_max,"step",p_step,"value",p_value},p_Attributes))
+} // END RangeField
+
+func RangeField(p_name, p_min, p_max, p_step, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("range",p_name,[]string{"min",p_min,"max",p_max,"step",p_step,"value",p_value},p_Attributes)...)
+} // END RangeField
+
+// Append a date input, values and limits as 2006-01-02
+func (p_HTML *T_HTML) DateField(p_name, p_min, p_max, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("date",p_name,[]string{"min",p_min,"max",p_max,"value",p_value},p_Attributes))
+} // END DateField
+
+func DateField(p_name, p_min, p_max, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("date",p_name,[]string{"min",p_min,"max",p_max,"value",p_value},p_Attributes)...)
+} // END DateField
+
+// Append a datetime-local input, values and limits as 2006-01-02T15:04
+func (p_HTML *T_HTML) DateTimeField(p_name, p_min, p_max, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("datetime-local",p_name,[]string{"min",p_min,"max",p_max,"value",p_value},p_Attributes))
+} // END DateTimeField
+
+func DateTimeField(p_name, p_min, p_max, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("datetime-local",p_name,[]string{"min",p_min,"max",p_max,"value",p_value},p_Attributes)...)
+} // END DateTimeField
+
+// Append a time input, values and limits as 15:04
+func (p_HTML *T_HTML) TimeField(p_name, p_min, p_max, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("time",p_name,[]string{"min",p_min,"max",p_max,"value",p_value},p_Attributes))
+} // END TimeField
+
+func TimeField(p_name, p_min, p_max, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("time",p_name,[]string{"min",p_min,"max",p_max,"value",p_value},p_Attributes)...)
+} // END TimeField
+
+// Append a color picker, the value as #rrggbb
+func (p_HTML *T_HTML) ColorField(p_name, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.input(p_name,inputArguments("color",p_name,[]string{"value",p_value},p_Attributes))
+} // END ColorField
+
+func ColorField(p_name, p_value string, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("color",p_name,[]string{"value",p_value},p_Attributes)...)
+} // END ColorField
+
+// Append a file input; p_accept: accepted types, e.g. "image/*,.pdf", empty: all.
+// The open form gets enctype="multipart/form-data", see ReadUploads.
+func (p_HTML *T_HTML) FileField(p_name, p_accept string, p_multiple bool, p_Attributes ...string) *T_HTML {
+	p_HTML.formMultipart()
+	return p_HTML.input(p_name,inputArguments("file",p_name,fileArguments(p_accept,p_multiple),p_Attributes))
+} // END FileField
+
+func FileField(p_name, p_accept string, p_multiple bool, p_Attributes ...string) string {
+	return Tag("input","",inputArguments("file",p_name,fileArguments(p_accept,p_multiple),p_Attributes)...)
+} // END FileField
+
+// Append a textarea; p_rows and p_cols may be empty
+func (p_HTML *T_HTML) TextArea(p_name, p_rows, p_cols, p_value string, p_Attributes ...string) *T_HTML {
+	Arguments := inputArguments("",p_name,[]string{"rows",p_rows,"cols",p_cols},p_Attributes)
+	return p_HTML.tagClosed("textarea",p_value,p_HTML.formInvalid(p_name,Arguments)...).formMessage(p_name)
+} // END TextArea
+
+func TextArea(p_name, p_rows, p_cols, p_value string, p_Attributes ...string) string {
+	return tagClosed("textarea",p_value,inputArguments("",p_name,[]string{"rows",p_rows,"cols",p_cols},p_Attributes)...)
+} // END TextArea
+
+// Append a group of radio buttons, each in a <label> with the class p_ItemClassName, the one with
+// the value p_checked is checked. The items are a map - the keys are the labels, as in SelectMenu -
+// or a slice of values; p_CompareFunc sorts the keys of a map or the values of a slice, nil: maps by CmpAsc, slices unsorted.
+// p_Attributes are added to every radio button.
+func (p_HTML *T_HTML) RadioGroup(p_name, p_ItemClassName, p_checked string, p_CompareFunc t_CompareFunc, p_Items any, p_Attributes ...string) *T_HTML {
+	Items,err := menuItems(p_Items,p_CompareFunc,"RadioGroup")
+	if err != nil {
+		p_HTML.setError(err) // the items read so far are shown
+	} // END if
+	for _,Item := range Items {
+		Arguments := radioArguments(p_name,p_checked,Item,p_Attributes)
+		if p_ItemClassName != "" {
+			p_HTML.TagOpen("label","class",p_ItemClassName)
+		} else {
+			p_HTML.TagOpen("label")
+		} // END if
+		p_HTML.Tag("input","",p_HTML.formInvalid(p_name,Arguments)...).AS(Item.label).TagCloseTop()
+	} // END for
+	return p_HTML.formMessage(p_name)
+} // END RadioGroup
+
+// Return a group of radio buttons as a string, errors of *sql.Rows panic
+func RadioGroup(p_name, p_ItemClassName, p_checked string, p_CompareFunc t_CompareFunc, p_Items any, p_Attributes ...string) string {
+	Items,err := menuItems(p_Items,p_CompareFunc,"RadioGroup")
+	if err != nil { panic(err) }
+	Result := ""
+	for _,Item := range Items {
+		Arguments := []string{}
+		appendAttribute("class",p_ItemClassName,&Arguments)
+		Result += Tag("label",Tag("input","",radioArguments(p_name,p_checked,Item,p_Attributes)...)+Item.label,Arguments...)
+	} // END for
+	return Result
+} // END RadioGroup
+
+// Append a <datalist> with suggestions for inputs with the attribute list="p_Id"; the items as in RadioGroup
+func (p_HTML *T_HTML) DataList(p_Id string, p_CompareFunc t_CompareFunc, p_Items any) *T_HTML {
+	Items,err := menuItems(p_Items,p_CompareFunc,"DataList")
+	if err != nil {
+		p_HTML.setError(err) // the items read so far are shown
+	} // END if
+	return p_HTML.AS(dataList(p_Id,Items))
+} // END DataList
+
+// Return a <datalist> as a string, errors of *sql.Rows panic
+func DataList(p_Id string, p_CompareFunc t_CompareFunc, p_Items any) string {
+	Items,err := menuItems(p_Items,p_CompareFunc,"DataList")
+	if err != nil { panic(err) }
+	return dataList(p_Id,Items)
+} // END DataList
+
+// Open a <fieldset> with a <legend>, an empty legend is left out
+func (p_HTML *T_HTML) FieldsetOpen(p_Legend string, p_Attributes ...string) *T_HTML {
+	p_HTML.TagOpen("fieldset",p_Attributes...)
+	if p_Legend != "" {
+		p_HTML.Legend(p_Legend)
+	} // END if
+	return p_HTML
+} // END FieldsetOpen
+
+func Fieldset(p_Legend, p_Content string, p_Attributes ...string) string {
+	if p_Legend != "" {
+		p_Content = Legend(p_Legend) + p_Content
+	} // END if
+	return tagClosed("fieldset",p_Content,p_Attributes...)
+} // END Fieldset
+
+func (p_HTML *T_HTML) Legend(p_Content string, p_Attributes ...string) *T_HTML { return p_HTML.Tag("legend",p_Content,p_Attributes...) }
+func Legend(p_Content string, p_Attributes ...string) string { return Tag("legend",p_Content,p_Attributes...) }
+
+// Append a label for the field with the id p_For
+func (p_HTML *T_HTML) Label(p_For, p_Content string, p_Attributes ...string) *T_HTML {
+	return p_HTML.tagClosed("label",p_Content,inputArguments("","",[]string{"for",p_For},p_Attributes)...)
+} // END Label
+
+func Label(p_For, p_Content string, p_Attributes ...string) string {
+	return tagClosed("label",p_Content,inputArguments("","",[]string{"for",p_For},p_Attributes)...)
+} // END Label
+
+// Append an <output> for the result of a calculation; p_For: the ids of the fields, separated by blanks
+func (p_HTML *T_HTML) Output(p_name, p_For, p_Content string, p_Attributes ...string) *T_HTML {
+	return p_HTML.tagClosed("output",p_Content,inputArguments("",p_name,[]string{"for",p_For},p_Attributes)...)
+} // END Output
+
+func Output(p_name, p_For, p_Content string, p_Attributes ...string) string {
+	return tagClosed("output",p_Content,inputArguments("",p_name,[]string{"for",p_For},p_Attributes)...)
+} // END Output
+
+// Append a button that resets the form
+func (p_HTML *T_HTML) ResetButton(p_label string, p_Attributes ...string) *T_HTML {
+	return p_HTML.tagClosed("button",p_label,inputArguments("reset","",nil,p_Attributes)...)
+} // END ResetButton
+
+func ResetButton(p_label string, p_Attributes ...string) string {
+	return tagClosed("button",p_label,inputArguments("reset","",nil,p_Attributes)...)
+} // END ResetButton
+
+// Append a button without action, for scripts and hx-* attributes
+func (p_HTML *T_HTML) Button(p_name, p_label, p_value string, p_Attributes ...string) *T_HTML {
+	return p_HTML.tagClosed("button",p_label,inputArguments("button",p_name,[]string{"value",p_value},p_Attributes)...)
+} // END Button
+
+func Button(p_name, p_label, p_value string, p_Attributes ...string) string {
+	return tagClosed("button",p_label,inputArguments("button",p_name,[]string{"value",p_value},p_Attributes)...)
+} // END Button
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// Attributes of a form field: type and name, the attribute/value pairs of p_Pairs with a value, then p_Attributes
+func inputArguments(p_Type, p_name string, p_Pairs []string, p_Attributes []string) []string {
+	Arguments := make([]string,0,len(p_Pairs)+len(p_Attributes)+4)
+	appendAttribute("type",p_Type,&Arguments)
+	appendAttribute("name",p_name,&Arguments)
+	for index := 0; index+1 < len(p_Pairs); index += 2 {
+		appendAttribute(p_Pairs[index],p_Pairs[index+1],&Arguments)
+	} // END for
+	return append(Arguments,p_Attributes...)
+} // END inputArguments
+
+// Append an input with the error attributes and the message of FormErrors
+func (p_HTML *T_HTML) input(p_name string, p_Arguments []string) *T_HTML {
+	return p_HTML.Tag("input","",p_HTML.formInvalid(p_name,p_Arguments)...).formMessage(p_name)
+} // END input
+
+// Add enctype="multipart/form-data" to the open <form>, which is needed for file inputs
+func (p_HTML *T_HTML) formMultipart() {
+	if p_HTML.formOffset == 0 || !slices.Contains(p_HTML.tagStack,"form") {
+		return
+	} // END if
+	p_HTML.groupInsert(p_HTML.formOffset,` enctype="multipart/form-data"`) // regions inside the form move too
+	p_HTML.formOffset = 0
+} // END formMultipart
+
+// Like Tag, but always with a closing tag: <textarea></textarea> must not be written as <textarea />
+func tagClosed(p_Name, p_Content string, p_Attributes ...string) string {
+	if p_Content != "" {
+		return Tag(p_Name,p_Content,p_Attributes...)
+	} // END if
+	return strings.TrimSuffix(Tag(p_Name,"",p_Attributes...)," />") + "></" + p_Name + ">"
+} // END tagClosed
+
+// Append a tag with closing tag, without the newlines of TagOpen, which would change the value of a textarea
+func (p_HTML *T_HTML) tagClosed(p_Name, p_Content string, p_Attributes ...string) *T_HTML {
+	p_HTML.AS(tagClosed(p_Name,p_Content,p_Attributes...))
+	if (p_HTML.nlMode & 0x02) != 0 {
+		p_HTML.NL()
+	} // END if
+	return p_HTML
+} // END tagClosed
+
+// Attributes accept and multiple of a file input
+func fileArguments(p_accept string, p_multiple bool) []string {
+	Arguments := []string{"accept",p_accept}
+	if p_multiple {
+		Arguments = append(Arguments,"multiple","multiple")
+	} // END if
+	return Arguments
+} // END fileArguments
+
+// Attributes of a radio button of a RadioGroup
+func radioArguments(p_name, p_checked string, p_Item t_FormOption, p_Attributes []string) []string {
+	Pairs := []string{"value",p_Item.value}
+	if p_Item.value == p_checked {
+		Pairs = append(Pairs,"checked","checked")
+	} // END if
+	return inputArguments("radio",p_name,Pairs,p_Attributes)
+} // END radioArguments
+
+// Values and labels of the items of a map or slice, see RadioGroup; the other sources of Select work too.
+// Errors of *sql.Rows are returned with the items read so far.
+func menuItems(p_Items any, p_CompareFunc t_CompareFunc, p_Caller string) ([]t_FormOption, error) {
+	return selectItems(p_Items,&T_Select{Compare: p_CompareFunc},p_Caller)
+} // END menuItems
+
+// The <datalist> of the items, see DataList
+func dataList(p_Id string, p_Items []t_FormOption) string {
+	Options := ""
+	for _,Item := range p_Items {
+		if Item.label == Item.value {
+			Options += tagClosed("option","","value",Item.value)
+		} else {
+			Options += tagClosed("option",Item.label,"value",Item.value)
+		} // END if
+	} // END for
+	return tagClosed("datalist",Options,"id",p_Id)
+} // END dataList
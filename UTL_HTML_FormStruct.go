@@ -0,0 +1,238 @@
+package UTL_HTML
+//
+// UTL_HTML_FormStruct
+// Version: $Id$
+//
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"reflect"
+	"time"
+)
+
+// *************************************************************************************
+// Forms from structs: the counterpart of TrTdStruct for editing. Every column of the
+// struct-type, as TrThStruct sees it, becomes a labelled form field with the current
+// value. The type of the field is derived from the Go type and can be changed with
+// the html struct-tag: Label, Input, Placeholder, Options and Required.
+//
+//  bool                 → checkbox
+//  int, uint, float     → number
+//  time.Time            → date, datetime-local if the Layout has a time
+//  string and others    → text
+//  Options='…'          → select menu, with multiple selection for slices
+
+// Append a complete form for a struct{} or *struct{}: FormOpen, the fields of FormFields and a submit button.
+//  - p_Action, p_Method - action and method of the form
+//  - p_FieldClass - CSS classname of the <div> around label and input of a field, empty: no <div>
+//  - p_Submit - label of the submit button, empty: no button
+//  - p_DataItem - the struct with the current values
+func (p_HTML *T_HTML) FormStruct(p_Action, p_Method, p_FieldClass, p_Submit string, p_DataItem any, p_Attributes ...string) *T_HTML {
+	p_HTML.FormOpen(p_Action,p_Method,p_Attributes...)
+	Depth := len(p_HTML.tagStack)
+	p_HTML.FormFields(p_FieldClass,p_DataItem)
+	if p_Submit != "" {
+		p_HTML.SubmitButton("",p_Submit,"")
+	} // END if
+	for len(p_HTML.tagStack) >= Depth {
+		p_HTML.TagCloseTop() // form
+	} // END for
+	return p_HTML
+} // END FormStruct
+
+// Append a labelled form field for each column of a struct{} or *struct{}, pre-filled with the current values.
+// The id of a field is the name of the column, "Address.City" for nested structs, the name is the Param
+// of the struct-tag or the name of the column, as expected by BindParameters; the columns
+// of a nested struct are put into a <fieldset> with the group header as <legend>.
+func (p_HTML *T_HTML) FormFields(p_FieldClass string, p_DataItem any) *T_HTML {
+	Struct := derefValue(reflect.ValueOf(p_DataItem))
+	if Struct.Kind() != reflect.Struct { panic(fmt.Sprintf("Unknown datatype used in FormFields: %T",p_DataItem)) }
+
+	Group := ""
+	for _,Column := range structMeta(Struct.Type()).Columns {
+		if Column.Group != Group {
+			if Group != "" {
+				p_HTML.TagCloseTop() // fieldset
+			} // END if
+			if Column.Group != "" {
+				p_HTML.TagOpen("fieldset").Tag("legend",Column.Group)
+			} // END if
+			Group = Column.Group
+		} // END if
+		p_HTML.formField(p_FieldClass,Column,Column.value(Struct))
+	} // END for
+	if Group != "" {
+		p_HTML.TagCloseTop() // fieldset
+	} // END if
+	return p_HTML
+} // END FormFields
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// Option of a select menu, a radio group or a datalist
+type t_FormOption struct {
+	value string
+	label string
+	group string        // label of the <optgroup>, see Select
+	sort  reflect.Value // the key of the sort order, see T_Select.Compare
+} // END t_FormOption
+
+// Append the label and the input of one column
+func (p_HTML *T_HTML) formField(p_FieldClass string, p_Column t_StructColumn, p_Value reflect.Value) {
+	Name := p_Column.param()
+	Input := formInputType(p_Column)
+	Text := formValue(p_Value,Input,p_Column.Tag)
+	Selected := formValues(p_Value)
+	if Typed := p_HTML.formTyped(Name); Typed != nil { // show what the user typed
+		Text, Selected = Typed[0], Typed
+	} // END if
+	if Input == "hidden" {
+		p_HTML.HiddenField(Name,html.EscapeString(Text),"id",p_Column.Name)
+		return
+	} // END if
+	Label := p_Column.Tag.Label
+	if Label == "" {
+		Label = p_Column.Header
+	} // END if
+	if p_FieldClass != "" {
+		p_HTML.TagOpen("div","class",p_FieldClass)
+	} // END if
+	p_HTML.Tag("label",Label,"for",p_Column.Name)
+
+	Arguments := []string{"id",p_Column.Name,"name",Name}
+	appendAttribute("placeholder",p_Column.Tag.Placeholder,&Arguments)
+	if p_Column.Tag.Required && Input != "checkbox" {
+		Arguments = append(Arguments,"required","required")
+	} // END if
+	if Input != "select" && Input != "checkbox" { // HTML5 constraints, see Validate
+		appendAttribute("minlength",p_Column.Tag.MinLength,&Arguments)
+		appendAttribute("maxlength",p_Column.Tag.MaxLength,&Arguments)
+		appendAttribute("min",p_Column.Tag.Min,&Arguments)
+		appendAttribute("max",p_Column.Tag.Max,&Arguments)
+		appendAttribute("pattern",html.EscapeString(p_Column.Tag.Pattern),&Arguments)
+	} // END if
+	Arguments = p_HTML.formInvalid(Name,Arguments)
+	switch Input {
+		case "select": {
+			if derefType(p_Column.Type).Kind() == reflect.Slice {
+				Arguments = append(Arguments,"multiple","multiple")
+			} // END if
+			p_HTML.TagOpen("select",Arguments...)
+			for _,Option := range formOptions(p_Column.Tag.Options) {
+				if slices.Contains(Selected,Option.value) {
+					p_HTML.Tag("option",Option.label,"value",Option.value,"selected","selected")
+				} else {
+					p_HTML.Tag("option",Option.label,"value",Option.value)
+				} // END if
+			} // END for
+			p_HTML.TagCloseTop() // select
+		} // END case
+		case "textarea": {
+			p_HTML.tagClosed("textarea",html.EscapeString(Text),Arguments...)
+		} // END case
+		case "checkbox": {
+			Arguments = append([]string{"type","checkbox"},Arguments...)
+			Arguments = append(Arguments,"value","true")
+			if Text == "true" || Text == "on" {
+				Arguments = append(Arguments,"checked","checked")
+			} // END if
+			p_HTML.Tag("input","",Arguments...)
+		} // END case
+		default: {
+			Arguments = append([]string{"type",Input},Arguments...)
+			if Input == "number" && formFloat(p_Column.Type) {
+				Arguments = append(Arguments,"step","any")
+			} // END if
+			appendAttribute("value",html.EscapeString(Text),&Arguments)
+			p_HTML.Tag("input","",Arguments...)
+		} // END default
+	} // END switch
+	p_HTML.formMessage(Name)
+	if p_FieldClass != "" {
+		p_HTML.TagCloseTop() // div
+	} // END if
+} // END formField
+
+// Type of the form field: Input of the struct-tag, select for Options, otherwise derived from the Go type
+func formInputType(p_Column t_StructColumn) string {
+	switch {
+		case p_Column.Tag.Input != "": return strings.ToLower(p_Column.Tag.Input)
+		case p_Column.Tag.Options != "": return "select"
+	} // END switch
+	Type := derefType(p_Column.Type)
+	switch Type.Kind() {
+		case reflect.Bool: return "checkbox"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		     reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		     reflect.Float32, reflect.Float64: return "number"
+	} // END switch
+	if Type == v_TimeType {
+		if strings.Contains(p_Column.Tag.Layout,"15") || strings.Contains(p_Column.Tag.Layout,"3:04") {
+			return "datetime-local"
+		} // END if
+		return "date"
+	} // END if
+	return "text"
+} // END formInputType
+
+// The value of a form field as text: dates in the format of the input type, numbers without exponent, nil and zero times as ""
+func formValue(p_Value reflect.Value, p_Input string, p_Tag t_HtmlTag) string {
+	Value := sqlValue(p_Value) // sql.NullString & Co.
+	if !Value.IsValid() {
+		return ""
+	} // END if
+	switch Data := Value.Interface().(type) {
+		case float32: return strconv.FormatFloat(float64(Data),'f',-1,32)
+		case float64: return strconv.FormatFloat(Data,'f',-1,64)
+	} // END switch
+	if Time,ok := Value.Interface().(time.Time); ok {
+		switch {
+			case Time.IsZero(): return ""
+			case p_Input == "date": return Time.Format("2006-01-02")
+			case p_Input == "datetime-local": return Time.Format("2006-01-02T15:04")
+			case p_Input == "time": return Time.Format("15:04")
+			case p_Tag.Layout != "": return Time.Format(p_Tag.Layout)
+		} // END switch
+	} // END if
+	return fmt.Sprint(Value.Interface())
+} // END formValue
+
+// The selected values of a select menu: the elements of a slice or the value
+func formValues(p_Value reflect.Value) []string {
+	Value := derefValue(p_Value)
+	if !Value.IsValid() {
+		return nil
+	} // END if
+	if Value.Kind() != reflect.Slice {
+		return []string{formValue(Value,"select",t_HtmlTag{})}
+	} // END if
+	Values := make([]string,0,Value.Len())
+	for index := 0; index < Value.Len(); index++ {
+		Values = append(Values,formValue(Value.Index(index),"select",t_HtmlTag{}))
+	} // END for
+	return Values
+} // END formValues
+
+// Options of the struct-tag: 'S=Small,M=Medium' or 'Small,Medium'
+func formOptions(p_Options string) []t_FormOption {
+	Options := []t_FormOption{}
+	for _,Entry := range strings.Split(p_Options,",") {
+		if Entry = strings.TrimSpace(Entry); Entry == "" { continue }
+		Value,Label,ok := strings.Cut(Entry,"=")
+		if !ok {
+			Label = Value
+		} // END if
+		Options = append(Options,t_FormOption{value: strings.TrimSpace(Value), label: strings.TrimSpace(Label)})
+	} // END for
+	return Options
+} // END formOptions
+
+// The field holds a float: the number input needs step="any" for decimals
+func formFloat(p_Type reflect.Type) bool {
+	Kind := derefType(p_Type).Kind()
+	return Kind == reflect.Float32 || Kind == reflect.Float64
+} // END formFloat
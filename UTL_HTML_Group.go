@@ -0,0 +1,278 @@
+package UTL_HTML
+//
+// UTL_HTML_Group
+// Version: $Id$
+//
+import (
+	"fmt"
+	"bytes"
+	"slices"
+	"strings"
+	"reflect"
+)
+
+// *************************************************************************************
+// Group-by sections: Consecutive rows with the same values in the group columns form a
+// group. The group value is printed once, either in a cell spanning the rows of the group
+// (rowspan) or in a header-row above the group. After each group follows a subtotal row
+// with the aggregates of the group, see Aggregate; the grand total is TfootAggregates.
+// []struct rows are sorted by the group columns, SQL queries have to be ordered by them.
+
+const (
+	GC_GroupRowspan string = "rowspan" // the group value is printed once in a cell spanning the rows of the group
+	GC_GroupHeader  string = "header"  // the group value is printed in a header-row, the group columns are hidden
+) // END const
+
+// One level of grouping
+type t_GroupLevel struct {
+	column     string         // name of the group column
+	value      string         // value of the current group
+	open       bool           // a group of this level has been started and not yet closed
+	rows       int            // number of rows in the current group, for rowspan
+	offset     int            // offset of the group cell in the buffer, for rowspan
+	aggColumns []*t_Aggregate // aggregates of the current group by column position
+} // END t_GroupLevel
+
+// Turn on grouping by the columns p_Columns, the outermost group first.
+//  - p_Mode - GC_GroupRowspan or GC_GroupHeader
+//  - p_Label - content of the first cell of the subtotal rows, "%s" is replaced by the group value, e.g. "Total %s"
+//  - p_Columns - names of the group columns: field names of structs or column names of the header-row
+//
+// Subtotal rows are only added when p_Label is not empty or aggregates are defined.
+// In rowspan mode, progressive flushing waits until the current group is complete.
+func (p_HTML *T_HTML) GroupBy(p_Mode, p_Label string, p_Columns ...string) *T_HTML {
+	if p_Mode != GC_GroupRowspan && p_Mode != GC_GroupHeader { panic(fmt.Sprintf("Unknown group mode: %s",p_Mode)) }
+	p_HTML.groupMode   = p_Mode
+	p_HTML.groupLabel  = p_Label
+	p_HTML.groupLevels = make([]*t_GroupLevel,len(p_Columns))
+	for index,Column := range p_Columns {
+		p_HTML.groupLevels[index] = &t_GroupLevel{column: Column}
+	} // END for
+	return p_HTML
+} // END GroupBy
+
+// Turn off grouping, e.g. before a second table in the same document
+func (p_HTML *T_HTML) GroupByEnd() *T_HTML {
+	p_HTML.groupMode   = ""
+	p_HTML.groupLabel  = ""
+	p_HTML.groupLevels = nil
+	p_HTML.groupNames  = nil
+	return p_HTML
+} // END GroupByEnd
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// Return the group level of a column, nil if it is no group column
+func (p_HTML *T_HTML) groupLevel(p_Name string) *t_GroupLevel {
+	for _,Level := range p_HTML.groupLevels {
+		if Level.column == p_Name {
+			return Level
+		} // END if
+	} // END for
+	return nil
+} // END groupLevel
+
+// The column is not printed: group columns in header mode
+func (p_HTML *T_HTML) groupHidden(p_Name string) bool {
+	return p_HTML.groupMode == GC_GroupHeader && p_HTML.groupLevel(p_Name) != nil
+} // END groupHidden
+
+// A group in rowspan mode is open, its rows must stay in the buffer
+func (p_HTML *T_HTML) groupPending() bool {
+	return p_HTML.groupMode == GC_GroupRowspan && len(p_HTML.groupLevels) > 0 && p_HTML.groupLevels[0].open
+} // END groupPending
+
+// Start a data-row: close the groups whose value has changed with their subtotal rows and open the new ones.
+func (p_HTML *T_HTML) groupRow(p_TrClass, p_TdClass string, p_Cells []t_Cell) {
+	p_HTML.groupNames = p_HTML.groupNames[:0]
+	for _,Cell := range p_Cells {
+		p_HTML.groupNames = append(p_HTML.groupNames,Cell.name)
+	} // END for
+	Names := p_HTML.groupNames
+	Values := make([]string,len(p_HTML.groupLevels))
+	Changed := len(p_HTML.groupLevels)
+	for index,Level := range p_HTML.groupLevels {
+		if Position := slices.Index(Names,Level.column); Position >= 0 {
+			Values[index] = groupText(p_Cells[Position].value)
+		} // END if
+		if Changed == len(p_HTML.groupLevels) && (!Level.open || Level.value != Values[index]) {
+			Changed = index
+		} // END if
+	} // END for
+	if Changed == len(p_HTML.groupLevels) {
+		return // same group as the last row
+	} // END if
+	p_HTML.groupClose(Changed,p_TrClass,p_TdClass)
+
+	Hidden := 0 // the group header spans the visible columns
+	for _,Name := range Names {
+		if p_HTML.groupHidden(Name) {
+			Hidden++
+		} // END if
+	} // END for
+	for index := Changed; index < len(p_HTML.groupLevels); index++ {
+		Level := p_HTML.groupLevels[index]
+		Level.value      = Values[index]
+		Level.open       = true
+		Level.rows       = 0
+		Level.aggColumns = nil
+		if p_HTML.groupMode == GC_GroupHeader {
+			p_HTML.helper_TrOpen(strings.TrimSpace(p_TrClass+" group")).
+			       helper_Tag("th",Level.value,p_TdClass,"","","colspan",fmt.Sprint(len(Names)-Hidden),"scope","rowgroup").
+			       helper_TrClose()
+		} // END if
+	} // END for
+} // END groupRow
+
+// The cell of column p_Name is left out: group columns in header mode and in all but the first row of a group in rowspan mode.
+// The position of the first cell of a group is recorded to add the rowspan attribute when the group is closed.
+func (p_HTML *T_HTML) groupSkip(p_Name string) bool {
+	Level := p_HTML.groupLevel(p_Name)
+	switch {
+		case Level == nil: return false
+		case p_HTML.groupMode == GC_GroupHeader: return true
+		case Level.rows > 0: return true
+	} // END switch
+	Level.offset = p_HTML.content.Len()
+	return false
+} // END groupSkip
+
+// Count a data-row or subtotal row in the open groups
+func (p_HTML *T_HTML) groupRowDone() {
+	for _,Level := range p_HTML.groupLevels {
+		if Level.open {
+			Level.rows++
+		} // END if
+	} // END for
+} // END groupRowDone
+
+// Close the groups from the innermost level up to level p_Level: add the rowspan attributes and the subtotal rows
+func (p_HTML *T_HTML) groupClose(p_Level int, p_TrClass, p_TdClass string) {
+	for index := len(p_HTML.groupLevels)-1; index >= p_Level; index-- {
+		Level := p_HTML.groupLevels[index]
+		if !Level.open { continue }
+		Level.open = false
+		if p_HTML.groupMode == GC_GroupRowspan && Level.rows > 1 {
+			p_HTML.groupInsert(Level.offset+len("<td"),fmt.Sprintf(` rowspan="%d"`,Level.rows))
+		} // END if
+		if p_HTML.groupLabel != "" || Level.aggColumns != nil {
+			p_HTML.groupSubtotal(Level,p_TrClass,p_TdClass)
+		} // END if
+	} // END for
+} // END groupClose
+
+// Close all groups at the end of the data
+func (p_HTML *T_HTML) groupEnd(p_TrClass, p_TdClass string) {
+	if len(p_HTML.groupLevels) > 0 {
+		p_HTML.groupClose(0,p_TrClass,p_TdClass)
+	} // END if
+} // END groupEnd
+
+// Subtotal row of a group: the label in the first free cell, the aggregates in their columns.
+// Cells spanned by the open outer groups are left out.
+func (p_HTML *T_HTML) groupSubtotal(p_Level *t_GroupLevel, p_TrClass, p_TdClass string) {
+	Label := p_HTML.groupLabel
+	if strings.Contains(Label,"%s") {
+		Label = fmt.Sprintf(Label,p_Level.value)
+	} // END if
+	p_HTML.helper_TrOpen(strings.TrimSpace(p_TrClass+" subtotal"))
+	for column,Name := range p_HTML.groupNames {
+		if Level := p_HTML.groupLevel(Name); p_HTML.groupHidden(Name) || (Level != nil && Level.open) {
+			continue
+		} // END if
+		var Aggregate *t_Aggregate
+		if column < len(p_Level.aggColumns) {
+			Aggregate = p_Level.aggColumns[column]
+		} // END if
+		switch {
+			case Aggregate != nil: p_HTML.helper_Tag("td",Aggregate.result(),p_TdClass,"",Aggregate.style,"data-aggregate",Aggregate.kind)
+			case Label != "": p_HTML.helper_Tag("th",Label,p_TdClass,"","","scope","row")
+			default: p_HTML.helper_Td("&nbsp;",p_TdClass,"","")
+		} // END switch
+		Label = "" // only in the first cell
+	} // END for
+	p_HTML.helper_TrClose()
+	p_HTML.groupRowDone()
+} // END groupSubtotal
+
+// Insert text into the buffer, e.g. the rowspan attribute of a group cell, and move the offsets of the other open groups and the regions
+func (p_HTML *T_HTML) groupInsert(p_Offset int, p_Text string) {
+	if p_Offset > p_HTML.content.Len() {
+		return // already flushed
+	} // END if
+	Tail := bytes.Clone(p_HTML.content.Bytes()[p_Offset:])
+	p_HTML.content.Truncate(p_Offset)
+	p_HTML.content.WriteString(p_Text)
+	p_HTML.content.Write(Tail)
+	for _,Level := range p_HTML.groupLevels {
+		if Level.open && Level.offset >= p_Offset {
+			Level.offset += len(p_Text)
+		} // END if
+	} // END for
+	p_HTML.regionShift(p_HTML.flushed+p_Offset,len(p_Text))
+} // END groupInsert
+
+// Add the value of a cell to the aggregates of the open groups
+func (p_HTML *T_HTML) groupAggregate(p_Column int, p_Aggregate *t_Aggregate, p_Value reflect.Value) {
+	for _,Level := range p_HTML.groupLevels {
+		if !Level.open { continue }
+		for len(Level.aggColumns) <= p_Column {
+			Level.aggColumns = append(Level.aggColumns,nil)
+		} // END for
+		if Level.aggColumns[p_Column] == nil {
+			Level.aggColumns[p_Column] = p_Aggregate.empty()
+		} // END if
+		Level.aggColumns[p_Column].add(p_Value)
+	} // END for
+} // END groupAggregate
+
+// Sort rows by the group columns, keeping the order within the groups
+func (p_HTML *T_HTML) groupSort(p_Rows []reflect.Value) {
+	if len(p_HTML.groupLevels) == 0 || len(p_Rows) == 0 {
+		return
+	} // END if
+	Names := p_HTML.columnNames
+	if Row := derefValue(p_Rows[0]); Row.Kind() == reflect.Struct {
+		Names = nil
+		for _,Column := range structMeta(Row.Type()).Columns {
+			Names = append(Names,Column.Name)
+		} // END for
+	} // END if
+	Positions := make([]int,0,len(p_HTML.groupLevels))
+	for _,Level := range p_HTML.groupLevels {
+		if Position := slices.Index(Names,Level.column); Position >= 0 {
+			Positions = append(Positions,Position)
+		} // END if
+	} // END for
+	slices.SortStableFunc(p_Rows,func(a,b reflect.Value) int {
+		for _,Position := range Positions {
+			A,B := derefValue(columnValue(a,Position)),derefValue(columnValue(b,Position))
+			switch {
+				case !A.IsValid() || !B.IsValid(): {
+					if Result := boolInt(A.IsValid()) - boolInt(B.IsValid()); Result != 0 {
+						return Result
+					} // END if
+				} // END case
+				default: {
+					if Result := CmpAsc(A,B); Result != 0 {
+						return Result
+					} // END if
+				} // END default
+			} // END switch
+		} // END for
+		return 0
+	})
+} // END groupSort
+
+// Text of a group value for comparison and the group header
+func groupText(p_Value reflect.Value) string {
+	Value := derefValue(p_Value)
+	if !Value.IsValid() {
+		return ""
+	} // END if
+	if Bytes,ok := Value.Interface().([]byte); ok {
+		return string(Bytes)
+	} // END if
+	return fmt.Sprint(Value.Interface())
+} // END groupText
@@ -0,0 +1,155 @@
+package UTL_HTML
+//
+// UTL_HTML_Htmx
+// Version: $Id$
+//
+import (
+	"net/http"
+)
+
+const (
+	GC_HxSwapInnerHTML   string = "innerHTML"
+	GC_HxSwapOuterHTML   string = "outerHTML"
+	GC_HxSwapBeforeBegin string = "beforebegin"
+	GC_HxSwapAfterBegin  string = "afterbegin"
+	GC_HxSwapBeforeEnd   string = "beforeend"
+	GC_HxSwapAfterEnd    string = "afterend"
+	GC_HxSwapDelete      string = "delete"
+	GC_HxSwapNone        string = "none"
+) // END const
+
+// A named region of the document: the content between the opening and the closing tag
+type t_Region struct {
+	depth int // size of the tag-stack with the opening tag of the region on top
+	start int // offset of the content in the document, including the flushed bytes
+	end   int // end of the content in the document, -1 while the region is open
+} // END t_Region
+
+// *************************************************************************************
+// Regions
+
+// Open a region as <div id="p_Id">. The content up to the closing tag can be written
+// alone with WriteHx(), so the same builder chain delivers the full page and the fragment.
+// FlushEvery doesn't flush while a region is open; the content of a region flushed by Flush() is lost for WriteHx.
+func (p_HTML *T_HTML) RegionOpen(p_Id string, p_Attributes ...string) *T_HTML {
+	p_HTML.TagOpen("div", append([]string{"id", p_Id}, p_Attributes...)...)
+	if p_HTML.regions == nil {
+		p_HTML.regions = make(map[string]*t_Region)
+	} // END if
+	p_HTML.regions[p_Id] = &t_Region{depth: len(p_HTML.tagStack), start: p_HTML.flushed + p_HTML.content.Len(), end: -1}
+	return p_HTML
+} // END RegionOpen
+
+// Mark the end of the region whose opening tag has just been popped from the tag-stack
+// not exported
+func (p_HTML *T_HTML) regionEnd() {
+	for _,Region := range p_HTML.regions {
+		if Region.end < 0 && Region.depth == len(p_HTML.tagStack)+1 {
+			Region.end = p_HTML.flushed + p_HTML.content.Len()
+		} // END if
+	} // END for
+} // END regionEnd
+
+// A region is open, see rowDone
+// not exported
+func (p_HTML *T_HTML) regionOpen() bool {
+	for _,Region := range p_HTML.regions {
+		if Region.end < 0 {
+			return true
+		} // END if
+	} // END for
+	return false
+} // END regionOpen
+
+// Move the regions behind p_Offset of the document by p_Length bytes, after an insert into the buffer
+// not exported
+func (p_HTML *T_HTML) regionShift(p_Offset, p_Length int) {
+	for _,Region := range p_HTML.regions {
+		if Region.start >= p_Offset {
+			Region.start += p_Length
+		} // END if
+		if Region.end >= p_Offset {
+			Region.end += p_Length
+		} // END if
+	} // END for
+} // END regionShift
+
+// Return the content of a region, an empty string if the region is unknown or has been flushed.
+// The content of a region that is still open ends at the current end of the document.
+func (p_HTML *T_HTML) Region(p_Id string) string {
+	Region,ok := p_HTML.regions[p_Id]
+	Start, End := 0, p_HTML.content.Len()
+	if ok {
+		Start = Region.start - p_HTML.flushed
+		if Region.end >= 0 {
+			End = Region.end - p_HTML.flushed
+		} // END if
+	} // END if
+	if !ok || Start < 0 || End < Start || End > p_HTML.content.Len() {
+		return ""
+	} // END if
+	return string(p_HTML.content.Bytes()[Start:End])
+} // END Region
+
+// Close all remaining tags and write the document to the ResponseWriter: When the request
+// has been made by htmx, only the content of the region p_Id is written, otherwise the full page.
+// If p_Id is empty the region is taken from the HX-Target header.
+func (p_HTML *T_HTML) WriteHx(w http.ResponseWriter, r *http.Request, p_Id string) {
+	p_HTML.TagCloseAll()
+	w.Header().Add("Vary", "HX-Request")
+	if !IsHxRequest(r) {
+		p_HTML.Write(w)
+		return
+	} // END if
+	if p_Id == "" {
+		p_Id = r.Header.Get("HX-Target")
+	} // END if
+	if _,ok := p_HTML.regions[p_Id]; !ok {
+		p_HTML.Write(w) // unknown region: the full page is better than nothing
+		return
+	} // END if
+	w.Write([]byte(p_HTML.Region(p_Id)))
+} // END WriteHx
+
+// Return true if the request has been made by htmx
+// In the map of ReadReqParameter() the header is available as "Hx-Request".
+func IsHxRequest(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+} // END IsHxRequest
+
+// *************************************************************************************
+// hx-* attributes
+// Each function returns an attribute/value pair, combine them with Attributes(), for example:
+//  DivOpen(Attributes(HxGet("/rows"),HxTarget("#result"),HxSwap(GC_HxSwapOuterHTML))...)
+
+// Concatenate lists of attributes into one list
+func Attributes(p_Lists ...[]string) []string {
+	Result := make([]string,0,2*len(p_Lists))
+	for _,List := range p_Lists {
+		Result = append(Result, List...)
+	} // END for
+	return Result
+} // END Attributes
+
+func HxGet(p_URL string) []string { return []string{"hx-get", p_URL} }
+func HxPost(p_URL string) []string { return []string{"hx-post", p_URL} }
+func HxPut(p_URL string) []string { return []string{"hx-put", p_URL} }
+func HxPatch(p_URL string) []string { return []string{"hx-patch", p_URL} }
+func HxDelete(p_URL string) []string { return []string{"hx-delete", p_URL} }
+func HxTarget(p_Selector string) []string { return []string{"hx-target", p_Selector} }
+func HxSwap(p_Swap string) []string { return []string{"hx-swap", p_Swap} }
+func HxTrigger(p_Trigger string) []string { return []string{"hx-trigger", p_Trigger} }
+func HxSelect(p_Selector string) []string { return []string{"hx-select", p_Selector} }
+func HxPushURL(p_URL string) []string { return []string{"hx-push-url", p_URL} }
+func HxConfirm(p_Question string) []string { return []string{"hx-confirm", p_Question} }
+func HxIndicator(p_Selector string) []string { return []string{"hx-indicator", p_Selector} }
+
+// *************************************************************************************
+// HX-* response headers, they must be set before the document is written
+
+func SetHxTrigger(w http.ResponseWriter, p_Event string) { w.Header().Set("HX-Trigger", p_Event) }
+func SetHxRedirect(w http.ResponseWriter, p_URL string) { w.Header().Set("HX-Redirect", p_URL) }
+func SetHxRefresh(w http.ResponseWriter) { w.Header().Set("HX-Refresh", "true") }
+func SetHxPushURL(w http.ResponseWriter, p_URL string) { w.Header().Set("HX-Push-Url", p_URL) }
+func SetHxRetarget(w http.ResponseWriter, p_Selector string) { w.Header().Set("HX-Retarget", p_Selector) }
+func SetHxReswap(w http.ResponseWriter, p_Swap string) { w.Header().Set("HX-Reswap", p_Swap) }
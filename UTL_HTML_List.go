@@ -5,11 +5,198 @@ package UTL_HTML
 //
 
 import (
-	//"fmt"
+	"fmt"
+	"slices"
+	"reflect"
 )
 
+// *************************************************************************************
+// Lists from data: List renders slices as <ul> or <ol> and maps and structs as <dl> with
+// <dt>/<dd>. Elements that are slices, maps or structs again become nested lists, so a
+// configuration or a JSON document is shown as it is nested. The classes are defined per
+// level, a render function can replace the content of single items.
+//
+//  []T / [N]T        → <ul> or <ol> with one <li> per element
+//  map[K]V           → <dl>, the keys in <dt> sorted by Compare, the values in <dd>
+//  struct / *struct  → <dl>, the headers of the fields in <dt> (see the html struct-tag), grouped fields in a nested <dl>
+//  time.Time, []byte, fmt.Stringer and sql.NullString & Co. are single values
+
+const (
+	GC_ListMaxDepth int = 32 // default depth of nested lists, deeper data is printed with fmt.Sprint
+) // END const
+
+// Definition of a list
+type T_List struct {
+	Ordered   bool                          // slices as <ol> instead of <ul>
+	ListClass []string                      // CSS classnames of <ul>, <ol> and <dl> per level: [0] for the outermost list, the last one for all deeper levels
+	ItemClass []string                      // CSS classnames of <li>, <dt> and <dd> per level
+	Compare   t_CompareFunc                 // sort order of the map keys, default: CmpAsc
+	MaxDepth  int                           // maximum depth of nested lists, 0: GC_ListMaxDepth
+	Render    func(p_Item T_ListItem) string // HTML content of an item that is not a nested list, "": the formatted value
+} // END T_List
+
+// An item of a list, passed to T_List.Render
+type T_ListItem struct {
+	Key   string // the map key or the header of the struct field, "" for elements of slices
+	Index int    // position within its list
+	Depth int    // level of the list, 0 for the outermost list
+	Value any    // the value of the item
+} // END T_ListItem
+
 func (p_HTML *T_HTML) OlOpen(p_Attributes ...string) *T_HTML { return p_HTML.TagOpen("ol", p_Attributes...) }
 func (p_HTML *T_HTML) UlOpen(p_Attributes ...string) *T_HTML { return p_HTML.TagOpen("ul", p_Attributes...) }
 func (p_HTML *T_HTML) LiOpen(p_Attributes ...string) *T_HTML { return p_HTML.TagOpen("li", p_Attributes...) }
 func (p_HTML *T_HTML) Li(p_Content string, p_Attributes ...string) *T_HTML { return p_HTML.Tag("li", p_Content, p_Attributes...) } 
 func (p_HTML *T_HTML) Lif(p_Class, p_Format string, p_Data ...any) *T_HTML { return p_HTML.Tagf("li",p_Class,p_Format,p_Data...) }
+
+// Append a list of a slice, map or struct, nested data becomes nested lists
+func (p_HTML *T_HTML) List(p_List T_List, p_DataItems any) *T_HTML {
+	DataItems := derefValue(reflect.ValueOf(p_DataItems))
+	if !listNested(DataItems) { panic(fmt.Sprintf("Unknown datatype used in List: %T",p_DataItems)) }
+	if p_List.MaxDepth <= 0 {
+		p_List.MaxDepth = GC_ListMaxDepth
+	} // END if
+	return p_HTML.listOpen(&p_List,DataItems,0)
+} // END List
+
+// Return a list of a slice, map or struct as a string
+func List(p_List T_List, p_DataItems any) string {
+	return New(GC_DocTypeNONE,0x00).List(p_List,p_DataItems).String()
+} // END List
+
+// Append an unordered list with one <li> per element of the slice
+func (p_HTML *T_HTML) UlSlice(p_UlClass, p_LiClass string, p_DataItems any) *T_HTML {
+	return p_HTML.List(T_List{ListClass: []string{p_UlClass}, ItemClass: []string{p_LiClass}},p_DataItems)
+} // END UlSlice
+
+// Append an ordered list with one <li> per element of the slice
+func (p_HTML *T_HTML) OlSlice(p_OlClass, p_LiClass string, p_DataItems any) *T_HTML {
+	return p_HTML.List(T_List{Ordered: true, ListClass: []string{p_OlClass}, ItemClass: []string{p_LiClass}},p_DataItems)
+} // END OlSlice
+
+// Append a description list of a map, the keys sorted by p_CompareFunc
+func (p_HTML *T_HTML) DlMap(p_DlClass, p_ItemClass string, p_CompareFunc t_CompareFunc, p_DataItems any) *T_HTML {
+	return p_HTML.List(T_List{ListClass: []string{p_DlClass}, ItemClass: []string{p_ItemClass}, Compare: p_CompareFunc},p_DataItems)
+} // END DlMap
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// Append the list of a slice, map or struct at the level p_Depth
+func (p_HTML *T_HTML) listOpen(p_List *T_List, p_Value reflect.Value, p_Depth int) *T_HTML {
+	switch p_Value.Kind() {
+		case reflect.Slice, reflect.Array: {
+			TagName := "ul"
+			if p_List.Ordered {
+				TagName = "ol"
+			} // END if
+			p_HTML.TagOpen(TagName,listClass(p_List.ListClass,p_Depth)...)
+			for index := 0; index < p_Value.Len(); index++ {
+				if p_HTML.Cancelled() { break } // client is gone
+				p_HTML.listItem(p_List,"li",T_ListItem{Index: index, Depth: p_Depth},p_Value.Index(index),listFormat)
+			} // END for
+			p_HTML.TagCloseTop()
+		} // END case
+		case reflect.Map: {
+			Keys := p_Value.MapKeys()
+			Compare := p_List.Compare
+			if Compare == nil {
+				Compare = CmpAsc // maps have no order of their own
+			} // END if
+			slices.SortStableFunc(Keys,Compare)
+			p_HTML.TagOpen("dl",listClass(p_List.ListClass,p_Depth)...)
+			for index,Key := range Keys {
+				if p_HTML.Cancelled() { break } // client is gone
+				Label := groupText(Key)
+				p_HTML.tagClosed("dt",Label,listClass(p_List.ItemClass,p_Depth)...)
+				p_HTML.listItem(p_List,"dd",T_ListItem{Key: Label, Index: index, Depth: p_Depth},p_Value.MapIndex(Key),listFormat)
+			} // END for
+			p_HTML.TagCloseTop()
+		} // END case
+		case reflect.Struct: {
+			p_HTML.listStruct(p_List,p_Value,structMeta(p_Value.Type()).Columns,false,p_Depth)
+		} // END case
+	} // END switch
+	return p_HTML
+} // END listOpen
+
+// Append the fields of a struct as <dl>; the columns of a group become a nested <dl> below the group header, p_Group: the columns are those of one group
+func (p_HTML *T_HTML) listStruct(p_List *T_List, p_Struct reflect.Value, p_Columns []t_StructColumn, p_Group bool, p_Depth int) {
+	p_HTML.TagOpen("dl",listClass(p_List.ListClass,p_Depth)...)
+	ItemClass := listClass(p_List.ItemClass,p_Depth)
+	for index := 0; index < len(p_Columns); index++ {
+		Column := p_Columns[index]
+		if Column.Group != "" && !p_Group && p_Depth+1 < p_List.MaxDepth {
+			End := index + 1
+			for End < len(p_Columns) && p_Columns[End].Group == Column.Group {
+				End++
+			} // END for
+			p_HTML.tagClosed("dt",Column.Group,ItemClass...)
+			p_HTML.TagOpen("dd",ItemClass...)
+			p_HTML.listStruct(p_List,p_Struct,p_Columns[index:End],true,p_Depth+1)
+			p_HTML.TagCloseTop()
+			index = End - 1
+			continue
+		} // END if
+		p_HTML.tagClosed("dt",Column.Header,ItemClass...)
+		p_HTML.listItem(p_List,"dd",T_ListItem{Key: Column.Header, Index: index, Depth: p_Depth},Column.value(p_Struct),func(p_Value reflect.Value) string {
+			return Column.formatCell(p_Struct,p_Value,"")
+		})
+	} // END for
+	p_HTML.TagCloseTop()
+} // END listStruct
+
+// Append an item: a nested list for slices, maps and structs, otherwise the content of Render or p_Format
+func (p_HTML *T_HTML) listItem(p_List *T_List, p_TagName string, p_Item T_ListItem, p_Value reflect.Value, p_Format func(reflect.Value) string) {
+	Class := listClass(p_List.ItemClass,p_Item.Depth)
+	if Value := derefValue(p_Value); listNested(Value) && p_Item.Depth+1 < p_List.MaxDepth {
+		p_HTML.TagOpen(p_TagName,Class...)
+		p_HTML.listOpen(p_List,Value,p_Item.Depth+1)
+		p_HTML.TagCloseTop()
+		return
+	} // END if
+	Content := ""
+	if p_List.Render != nil {
+		if p_Value.IsValid() && p_Value.CanInterface() {
+			p_Item.Value = p_Value.Interface()
+		} // END if
+		Content = p_List.Render(p_Item)
+	} // END if
+	if Content == "" {
+		Content = p_Format(p_Value)
+	} // END if
+	p_HTML.tagClosed(p_TagName,Content,Class...)
+} // END listItem
+
+// Format a value of a slice or map
+func listFormat(p_Value reflect.Value) string {
+	return formatValue(p_Value,t_HtmlTag{})
+} // END listFormat
+
+// The value becomes a nested list: slices, arrays, maps and structs that don't print themselves
+func listNested(p_Value reflect.Value) bool {
+	if !p_Value.IsValid() {
+		return false
+	} // END if
+	Type := p_Value.Type()
+	switch Type.Kind() {
+		case reflect.Slice, reflect.Array: return Type.Elem().Kind() != reflect.Uint8
+		case reflect.Map: return true
+		case reflect.Struct: {
+			return Type != v_TimeType && !Type.Implements(v_StringerType) && !reflect.PointerTo(Type).Implements(v_StringerType) &&
+				!Type.Implements(valuerType) && !reflect.PointerTo(Type).Implements(valuerType)
+		} // END case
+	} // END switch
+	return false
+} // END listNested
+
+// The class attribute of a level: the class of the level or the last class for deeper levels
+func listClass(p_Classes []string, p_Depth int) []string {
+	if len(p_Classes) == 0 {
+		return nil
+	} // END if
+	if Class := p_Classes[min(p_Depth,len(p_Classes)-1)]; Class != "" {
+		return []string{"class",Class}
+	} // END if
+	return nil
+} // END listClass
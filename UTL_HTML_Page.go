@@ -0,0 +1,201 @@
+package UTL_HTML
+//
+// UTL_HTML_Page
+// Version: $Id$
+//
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"reflect"
+)
+
+const (
+	GC_ParamPage      string = "page"       // request parameter with the page number, starting with 1
+	GC_ParamSize      string = "size"       // request parameter with the number of rows per page
+	GC_ParamAfter     string = "after"      // request parameter with the key of the last row of the previous page (keyset pagination)
+	GC_ParamAfterType string = "after_type" // request parameter with the type of a numeric key in "after": int or float
+
+	GC_MaxPageSize int = 1000 // larger page sizes requested by the client are ignored
+) // END const
+
+// Turn on pagination: The page number and the page size are taken from the request parameters
+// "page" and "size" of ReadReqParameter, p_PageSize is used when no valid size is requested.
+// TrTdSlice and TrTdMap render only the rows of the current page (after sorting), SQL queries
+// are limited with LimitQuery or KeysetQuery. The parameters named in p_KeepParams are carried
+// along in the links of PageNav.
+func (p_HTML *T_HTML) Paginate(p_Parameter map[string]string, p_PageSize int, p_KeepParams ...string) *T_HTML {
+	p_HTML.pageActive = true
+	p_HTML.pageTotal  = -1
+	p_HTML.pageSize   = p_PageSize
+	if Size,err := strconv.Atoi(p_Parameter[GC_ParamSize]); err == nil && Size > 0 && Size <= GC_MaxPageSize {
+		p_HTML.pageSize = Size
+		p_HTML.keepParams(p_Parameter,GC_ParamSize)
+	} // END if
+	if p_HTML.pageSize < 1 {
+		p_HTML.pageSize = 1
+	} // END if
+	p_HTML.pageNum = 1
+	if Page,err := strconv.Atoi(p_Parameter[GC_ParamPage]); err == nil && Page > 1 {
+		p_HTML.pageNum = Page
+	} // END if
+	p_HTML.keyAfter = p_Parameter[GC_ParamAfter]
+	p_HTML.keyType  = p_Parameter[GC_ParamAfterType]
+	p_HTML.keepParams(p_Parameter,p_KeepParams...)
+	return p_HTML
+} // END Paginate
+
+// Turn off pagination, e.g. before a second table in the same document
+func (p_HTML *T_HTML) PaginateEnd() *T_HTML {
+	p_HTML.pageActive = false
+	p_HTML.keyColumn  = ""
+	return p_HTML
+} // END PaginateEnd
+
+// Set the total number of rows, necessary for SQL queries to render the page numbers in PageNav
+// The count can be retrieved with the query built by CountQuery.
+func (p_HTML *T_HTML) SetPageTotal(p_Total int) *T_HTML {
+	p_HTML.pageTotal = p_Total
+	p_HTML.pageNum   = min(p_HTML.pageNum,p_HTML.lastPage())
+	return p_HTML
+} // END SetPageTotal
+
+// Return the query for the number of rows of p_Query
+func CountQuery(p_Query string) string {
+	return fmt.Sprintf("SELECT COUNT(*) FROM (%s) t_count",p_Query)
+} // END CountQuery
+
+// Return the query limited to the rows of the current page with LIMIT/OFFSET and sorted by p_OrderBy,
+// e.g. "Breed" or the result of SortOrder. ORDER BY and LIMIT are added to the same SELECT around the
+// query, which should not be ordered itself: the order of a sub-query is not kept by SQL.
+// An empty p_OrderBy leaves the order to the database, the pages may overlap then.
+func (p_HTML *T_HTML) LimitQuery(p_Query, p_OrderBy string) string {
+	Order := ""
+	if p_OrderBy != "" {
+		Order = " ORDER BY " + p_OrderBy
+	} // END if
+	if !p_HTML.pageActive {
+		if Order == "" {
+			return p_Query
+		} // END if
+		return fmt.Sprintf("SELECT * FROM (%s) t_page%s",p_Query,Order)
+	} // END if
+	return fmt.Sprintf("SELECT * FROM (%s) t_page%s LIMIT %d OFFSET %d",p_Query,Order,p_HTML.pageSize,(p_HTML.pageNum-1)*p_HTML.pageSize)
+} // END LimitQuery
+
+// Keyset pagination for large tables: Return the query for the rows following the key of the
+// request parameter "after", ordered by the unique column p_KeyColumn, and the arguments for the
+// query. LIMIT/OFFSET reads and skips all rows of the previous pages, the keyset query uses the
+// index of the key column instead. PageNav renders "first" and "next" links only.
+// The placeholder for the argument is "?", replace it for drivers using another syntax. The argument
+// has the type of the key of the previous page: numbers, e.g. of ROW_NUMBER(), are bound as int64 or
+// float64, as a string they would be compared as text.
+func (p_HTML *T_HTML) KeysetQuery(p_Query, p_KeyColumn string) (string, []any) {
+	if !p_HTML.pageActive {
+		return p_Query,nil
+	} // END if
+	p_HTML.keyColumn   = p_KeyColumn
+	p_HTML.keyNext     = ""
+	p_HTML.keyNextType = ""
+	p_HTML.keyMore     = false
+	if p_HTML.keyAfter == "" {
+		return fmt.Sprintf("SELECT * FROM (%s) t_page ORDER BY %s LIMIT %d",p_Query,p_KeyColumn,p_HTML.pageSize+1),nil
+	} // END if
+	return fmt.Sprintf("SELECT * FROM (%s) t_page WHERE %s > ? ORDER BY %s LIMIT %d",p_Query,p_KeyColumn,p_KeyColumn,p_HTML.pageSize+1),[]any{p_HTML.keysetArgument()}
+} // END KeysetQuery
+
+// Append the navigation bar: first, previous, numbered pages, next and last page.
+// Other request parameters are kept as configured in Paginate and Sortable.
+func (p_HTML *T_HTML) PageNav(p_Class string, p_Attributes ...string) *T_HTML {
+	if !p_HTML.pageActive {
+		return p_HTML
+	} // END if
+	p_HTML.TagOpen("nav",append([]string{"class",p_Class,"aria-label","Pagination"},p_Attributes...)...)
+	if p_HTML.keyColumn != "" { // keyset pagination
+		p_HTML.pageLink("«","first page",p_HTML.keyAfter != "",p_HTML.linkURL())
+		Next := []string{GC_ParamAfter,p_HTML.keyNext}
+		if p_HTML.keyNextType != "" {
+			Next = append(Next,GC_ParamAfterType,p_HTML.keyNextType)
+		} // END if
+		p_HTML.pageLink("›","next page",p_HTML.keyMore,p_HTML.linkURL(Next...))
+		return p_HTML.TagCloseTop() // nav
+	} // END if
+
+	Last := p_HTML.lastPage()
+	p_HTML.pageLink("«","first page",p_HTML.pageNum > 1,p_HTML.linkURL(GC_ParamPage,"1"))
+	p_HTML.pageLink("‹","previous page",p_HTML.pageNum > 1,p_HTML.linkURL(GC_ParamPage,strconv.Itoa(p_HTML.pageNum-1)))
+	for Page := max(1,p_HTML.pageNum-2); Page <= min(Last,p_HTML.pageNum+2); Page++ {
+		if Page == p_HTML.pageNum {
+			p_HTML.Span(strconv.Itoa(Page),"aria-current","page")
+		} else {
+			p_HTML.A(strconv.Itoa(Page),p_HTML.linkURL(GC_ParamPage,strconv.Itoa(Page)),fmt.Sprintf("page %d",Page))
+		} // END if
+	} // END for
+	p_HTML.pageLink("›","next page",p_HTML.pageNum < Last,p_HTML.linkURL(GC_ParamPage,strconv.Itoa(p_HTML.pageNum+1)))
+	p_HTML.pageLink("»","last page",p_HTML.pageNum < Last,p_HTML.linkURL(GC_ParamPage,strconv.Itoa(Last)))
+	return p_HTML.TagCloseTop() // nav
+} // END PageNav
+
+// -------------------------------------------------------------------
+// not exported helper functions
+
+// Link of the navigation bar, a disabled link is rendered as <span>
+func (p_HTML *T_HTML) pageLink(p_Label, p_Title string, p_Enabled bool, p_Href string) *T_HTML {
+	if p_Enabled {
+		return p_HTML.A(p_Label,p_Href,p_Title)
+	} // END if
+	return p_HTML.Span(p_Label,"aria-disabled","true")
+} // END pageLink
+
+// Return the number of the last page, the current page if the total is unknown
+func (p_HTML *T_HTML) lastPage() int {
+	if p_HTML.pageTotal < 0 {
+		return p_HTML.pageNum
+	} // END if
+	return max(1,(p_HTML.pageTotal+p_HTML.pageSize-1)/p_HTML.pageSize)
+} // END lastPage
+
+// Return the index of the key column for keyset pagination, -1 if not active
+func (p_HTML *T_HTML) keysetIndex(p_ColumnNames []string) int {
+	if !p_HTML.pageActive || p_HTML.keyColumn == "" {
+		return -1
+	} // END if
+	return slices.Index(p_ColumnNames,p_HTML.keyColumn)
+} // END keysetIndex
+
+// Remember the key of the last row of the page and whether it is a number, see KeysetQuery
+func (p_HTML *T_HTML) keysetNext(p_Key reflect.Value) {
+	p_HTML.keyNext, p_HTML.keyNextType = groupText(p_Key), ""
+	switch p_Key.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		     reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64: p_HTML.keyNextType = "int"
+		case reflect.Float32, reflect.Float64: p_HTML.keyNextType = "float"
+	} // END switch
+} // END keysetNext
+
+// The argument of KeysetQuery: the key of "after" with the type of "after_type", a string if it isn't a number
+func (p_HTML *T_HTML) keysetArgument() any {
+	switch p_HTML.keyType {
+		case "int": {
+			if Number,err := strconv.ParseInt(p_HTML.keyAfter,10,64); err == nil {
+				return Number
+			} // END if
+		} // END case
+		case "float": {
+			if Number,err := strconv.ParseFloat(p_HTML.keyAfter,64); err == nil {
+				return Number
+			} // END if
+		} // END case
+	} // END switch
+	return p_HTML.keyAfter
+} // END keysetArgument
+
+// Return the rows of the current page and remember the total number of rows
+func pageSlice[E any](p_HTML *T_HTML, p_Rows []E) []E {
+	if !p_HTML.pageActive {
+		return p_Rows
+	} // END if
+	p_HTML.SetPageTotal(len(p_Rows))
+	From := (p_HTML.pageNum-1)*p_HTML.pageSize
+	return p_Rows[From:min(len(p_Rows),From+p_HTML.pageSize)]
+} // END pageSlice
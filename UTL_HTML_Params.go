@@ -0,0 +1,236 @@
+package UTL_HTML
+//
+// UTL_HTML_Params
+// Version: $Id$
+//
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// *************************************************************************************
+// Request parameters: ReadParams keeps the sources of a request apart - headers, URL,
+// form fields, cookies and credentials - so a form field can't pose as a header or as the
+// authenticated user. Multi-values stay lists. The typed getters return a default for
+// missing or empty parameters; values that can't be converted return the default too and
+// are collected as errors, see Errors. The conversions are those of BindParameters.
+// JSON bodies are decoded into the form fields: {"page": 2, "tags": ["a","b"]} → page=2, tags=a, tags=b.
+
+const (
+	GC_ParamsMaxBody int64 = 10 << 20 // maximum size of a JSON body
+) // END const
+
+// The parameters of a request
+type T_Params struct {
+	Header T_ParamValues  // headers, the names are case-insensitive
+	Query  T_ParamValues  // parameters of the URL
+	Form   T_ParamValues  // form fields of the body: urlencoded, multipart or the members of a JSON object
+	Cookie T_ParamValues  // cookies
+	Auth   T_Credentials  // credentials of Basic authentication
+	JSON   any            // the decoded body of a JSON request, nil for other requests
+	errors T_FieldErrors  // conversion errors of the getters
+} // END T_Params
+
+// Credentials of the Authorization header, as sent by the client: not verified
+type T_Credentials struct {
+	User     string
+	Password string
+	OK       bool // the request has a valid Basic Authorization header
+} // END T_Credentials
+
+// One namespace of the parameters
+type T_ParamValues struct {
+	values    url.Values
+	canonical bool             // header names
+	errors    *T_FieldErrors   // the errors of T_Params
+} // END T_ParamValues
+
+// Read the parameters of a request: parse the URL, an urlencoded, multipart or JSON body, the cookies and the credentials.
+// A JSON body is restored after reading, so the handler can decode it again.
+func ReadParams(r *http.Request) (*T_Params, error) {
+	return readParams(r,true)
+} // END ReadParams
+
+// The conversion errors of the getters by parameter name, nil if there are none
+func (p_Params *T_Params) Errors() T_FieldErrors {
+	return p_Params.errors
+} // END Errors
+
+// The URL and form values as map for Paginate and Sortable, multi-values are separated by ";" as in ReadReqParameter.
+// Headers and credentials are not included.
+func (p_Params *T_Params) Map() map[string]string {
+	Values := make(url.Values)
+	for Name,List := range p_Params.Form.values {
+		Values[Name] = slices.Clone(List)
+	} // END for
+	for Name,List := range p_Params.Query.values {
+		Values[Name] = append(Values[Name],List...) // form fields first, as in r.Form
+	} // END for
+	Result := make(map[string]string)
+	for Name,List := range Values {
+		Result[Name] = strings.Join(List,";")
+	} // END for
+	return Result
+} // END Map
+
+// The parameter exists, even if it is empty
+func (p_Values T_ParamValues) Has(p_Name string) bool {
+	_,ok := p_Values.values[p_Values.key(p_Name)]
+	return ok
+} // END Has
+
+// All values of a multi-value parameter, nil if it is missing
+func (p_Values T_ParamValues) Strings(p_Name string) []string {
+	return slices.Clone(p_Values.values[p_Values.key(p_Name)])
+} // END Strings
+
+// The names of the parameters, sorted
+func (p_Values T_ParamValues) Names() []string {
+	Names := make([]string,0,len(p_Values.values))
+	for Name := range p_Values.values {
+		Names = append(Names,Name)
+	} // END for
+	sort.Strings(Names)
+	return Names
+} // END Names
+
+// The first value as sent, p_Default if the parameter is missing or contains only spaces
+func (p_Values T_ParamValues) String(p_Name, p_Default string) string {
+	return ParamValue(p_Values,p_Name,p_Default)
+} // END String
+
+// The first value as int
+func (p_Values T_ParamValues) Int(p_Name string, p_Default int) int {
+	return ParamValue(p_Values,p_Name,p_Default)
+} // END Int
+
+// The first value as int64
+func (p_Values T_ParamValues) Int64(p_Name string, p_Default int64) int64 {
+	return ParamValue(p_Values,p_Name,p_Default)
+} // END Int64
+
+// The first value as float64
+func (p_Values T_ParamValues) Float(p_Name string, p_Default float64) float64 {
+	return ParamValue(p_Values,p_Name,p_Default)
+} // END Float
+
+// The first value as bool: true for on, true, yes and 1, false for off, false, no and 0
+func (p_Values T_ParamValues) Bool(p_Name string, p_Default bool) bool {
+	return ParamValue(p_Values,p_Name,p_Default)
+} // END Bool
+
+// The first value as time.Time, in the formats of date, datetime-local and time inputs or RFC 3339
+func (p_Values T_ParamValues) Time(p_Name string, p_Default time.Time) time.Time {
+	return ParamValue(p_Values,p_Name,p_Default)
+} // END Time
+
+// The value of a parameter of any type BindParameters can decode, e.g. []int for a multi-value parameter.
+// p_Default is returned if the parameter is missing or empty, or if it can't be converted; the error is kept in T_Params.Errors.
+func ParamValue[T any](p_Values T_ParamValues, p_Name string, p_Default T) T {
+	List := p_Values.values[p_Values.key(p_Name)]
+	if len(List) == 0 || (len(List) == 1 && strings.TrimSpace(List[0]) == "") {
+		return p_Default
+	} // END if
+	Value := reflect.New(reflect.TypeFor[T]()).Elem()
+	if err := bindValue(Value,List,t_HtmlTag{}); err != nil {
+		if p_Values.errors != nil {
+			if *p_Values.errors == nil {
+				*p_Values.errors = make(T_FieldErrors)
+			} // END if
+			p_Values.errors.Add(p_Name,err.Error())
+		} // END if
+		return p_Default
+	} // END if
+	return Value.Interface().(T)
+} // END ParamValue
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// Read the parameters of a request, p_JSON: decode a JSON body into the form fields
+func readParams(r *http.Request, p_JSON bool) (*T_Params, error) {
+	Params := &T_Params{}
+	Params.Header = T_ParamValues{values: url.Values(r.Header), canonical: true, errors: &Params.errors}
+	Params.Query = T_ParamValues{values: r.URL.Query(), errors: &Params.errors}
+	Params.Form = T_ParamValues{values: make(url.Values), errors: &Params.errors}
+	Params.Cookie = T_ParamValues{values: make(url.Values), errors: &Params.errors}
+	for _,Cookie := range r.Cookies() {
+		Params.Cookie.values.Add(Cookie.Name,Cookie.Value)
+	} // END for
+	Params.Auth.User, Params.Auth.Password, Params.Auth.OK = r.BasicAuth()
+
+	if MediaType,_,_ := mime.ParseMediaType(r.Header.Get("Content-Type")); p_JSON && (MediaType == "application/json" || strings.HasSuffix(MediaType,"+json")) {
+		if r.Body == nil {
+			return Params, nil
+		} // END if
+		Body,err := io.ReadAll(io.LimitReader(r.Body,GC_ParamsMaxBody+1))
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(Body),r.Body)) // the handler may decode the body again
+		switch {
+			case err != nil: return Params, err
+			case int64(len(Body)) > GC_ParamsMaxBody: return Params, fmt.Errorf("the JSON body is larger than %d bytes",GC_ParamsMaxBody)
+			case len(strings.TrimSpace(string(Body))) == 0: return Params, nil
+		} // END switch
+		Decoder := json.NewDecoder(bytes.NewReader(Body))
+		Decoder.UseNumber() // numbers keep their text, large integers are not rounded
+		if err := Decoder.Decode(&Params.JSON); err != nil {
+			return Params, err
+		} // END if
+		paramsJSON(Params.Form.values,"",Params.JSON)
+		return Params, nil
+	} // END if
+	if err := parseForm(r); err != nil {
+		return Params, err
+	} // END if
+	for Name,Values := range r.PostForm {
+		Params.Form.values[Name] = slices.Clone(Values)
+	} // END for
+	return Params, nil
+} // END readParams
+
+// The name of a parameter in the map, header names are canonical
+func (p_Values T_ParamValues) key(p_Name string) string {
+	if p_Values.canonical {
+		return textproto.CanonicalMIMEHeaderKey(p_Name)
+	} // END if
+	return p_Name
+} // END key
+
+// Flatten a decoded JSON value into form values: members of objects become "name" or "parent.name", arrays multi-values
+func paramsJSON(p_Values url.Values, p_Name string, p_Data any) {
+	switch Data := p_Data.(type) {
+		case map[string]any: {
+			for Name,Member := range Data {
+				if p_Name != "" {
+					Name = p_Name + "." + Name
+				} // END if
+				paramsJSON(p_Values,Name,Member)
+			} // END for
+		} // END case
+		case []any: {
+			for _,Element := range Data {
+				paramsJSON(p_Values,p_Name,Element)
+			} // END for
+		} // END case
+		case nil: {
+			if p_Name != "" {
+				p_Values[p_Name] = append(p_Values[p_Name],"")
+			} // END if
+		} // END case
+		default: {
+			if p_Name != "" {
+				p_Values.Add(p_Name,fmt.Sprint(Data))
+			} // END if
+		} // END default
+	} // END switch
+} // END paramsJSON
@@ -0,0 +1,294 @@
+package UTL_HTML
+//
+// UTL_HTML_Pivot
+// Version: $Id$
+//
+import (
+	"fmt"
+	"slices"
+	"reflect"
+	"database/sql"
+)
+
+// *************************************************************************************
+// Pivot tables: long-format data becomes a matrix. One field gives the row keys, one the
+// column keys and one the values, aggregated per cell. The axes are sorted, missing cells
+// get a placeholder, row and column totals are optional.
+
+// Definition of a pivot table
+type T_Pivot struct {
+	RowField      string         // field or column with the row keys, not used for maps
+	ColumnField   string         // field or column with the column keys, not used for maps
+	ValueField    string         // field or column with the values, not used for maps; empty: count the rows
+	Aggregate     string         // aggregate of the values per cell, see Aggregate; default: "sum", without ValueField "count"
+	RowHeader     string         // content of the top-left cell
+	Empty         string         // placeholder for missing cells, default: "&nbsp;"
+	RowCompare    t_CompareFunc  // sort order of the rows, default: CmpAsc
+	ColumnCompare t_CompareFunc  // sort order of the columns, default: CmpAsc
+	RowTotals     bool           // add a column with the totals of the rows
+	ColumnTotals  bool           // add a row with the totals of the columns
+	TotalLabel    string         // header of the totals, default: "Total"
+} // END T_Pivot
+
+// Append <thead>, <tbody> and with ColumnTotals <tfoot> of a pivot table to the open <table>
+//  - p_TrClass - CSS classname for <tr>
+//  - p_ThClass - CSS classname for <th>, the column and row keys
+//  - p_TdClass - CSS classname for <td>
+//  - p_Pivot - the definition of the pivot table
+//  - p_DataItems - the data: []struct, []*struct, map[K1]map[K2]V or *sql.Rows
+//
+// For a map[K1]map[K2]V, K1 are the row keys, K2 the column keys and V the values.
+func (p_HTML *T_HTML) Pivot(p_TrClass, p_ThClass, p_TdClass string, p_Pivot T_Pivot, p_DataItems any) *T_HTML {
+	if p_Pivot.Aggregate == "" {
+		p_Pivot.Aggregate = GC_AggSum
+		if p_Pivot.ValueField == "" && reflect.ValueOf(p_DataItems).Kind() != reflect.Map {
+			p_Pivot.Aggregate = GC_AggCount // no values: count the rows
+		} // END if
+	} // END if
+	if p_Pivot.Empty == "" {
+		p_Pivot.Empty = "&nbsp;"
+	} // END if
+	if p_Pivot.TotalLabel == "" {
+		p_Pivot.TotalLabel = "Total"
+	} // END if
+	if p_Pivot.RowCompare == nil {
+		p_Pivot.RowCompare = CmpAsc
+	} // END if
+	if p_Pivot.ColumnCompare == nil {
+		p_Pivot.ColumnCompare = CmpAsc
+	} // END if
+
+	Matrix := &t_PivotMatrix{pivot: &p_Pivot, cells: make(map[[2]int]*t_Aggregate)}
+	switch DataItems := p_DataItems.(type) {
+		case *sql.Rows: Matrix.readSqlRows(p_HTML,DataItems)
+		default: {
+			Data := reflect.ValueOf(p_DataItems)
+			switch Data.Kind() {
+				case reflect.Slice: Matrix.readSlice(p_HTML,Data)
+				case reflect.Map: Matrix.readMap(Data)
+				default: panic(fmt.Sprintf("Unknown datatype used in Pivot: %T",p_DataItems))
+			} // END switch
+		} // END default
+	} // END switch
+	return Matrix.render(p_HTML,p_TrClass,p_ThClass,p_TdClass)
+} // END Pivot
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// The distinct keys of an axis
+type t_PivotAxis struct {
+	keys   []reflect.Value
+	labels []string
+	index  map[string]int // text of the key → position in keys
+} // END t_PivotAxis
+
+// The aggregated cells of a pivot table
+type t_PivotMatrix struct {
+	pivot     *T_Pivot
+	rows      t_PivotAxis
+	columns   t_PivotAxis
+	cells     map[[2]int]*t_Aggregate // (row,column) → aggregate
+	rowTotals []*t_Aggregate
+	colTotals []*t_Aggregate
+	total     *t_Aggregate
+	tag       t_HtmlTag               // html struct-tag of the value field, for the Format
+	style     string                  // style attribute of the cells, from the value field
+} // END t_PivotMatrix
+
+// Return the position of a key on the axis, new keys are appended
+func (p_Axis *t_PivotAxis) add(p_Key reflect.Value, p_Label string) int {
+	Text := groupText(p_Key)
+	if Position,ok := p_Axis.index[Text]; ok {
+		return Position
+	} // END if
+	if p_Axis.index == nil {
+		p_Axis.index = make(map[string]int)
+	} // END if
+	Key := derefValue(p_Key)
+	if Key.IsValid() && Key.Kind() == reflect.Slice && Key.Type().Elem().Kind() == reflect.Uint8 {
+		Key = reflect.ValueOf(Text) // []byte from database/sql
+	} // END if
+	p_Axis.index[Text] = len(p_Axis.keys)
+	p_Axis.keys = append(p_Axis.keys,Key)
+	p_Axis.labels = append(p_Axis.labels,p_Label)
+	return len(p_Axis.keys)-1
+} // END add
+
+// Return the positions of the keys in sort order, nil keys first
+func (p_Axis *t_PivotAxis) order(p_Compare t_CompareFunc) []int {
+	Order := make([]int,len(p_Axis.keys))
+	for index := range Order {
+		Order[index] = index
+	} // END for
+	slices.SortStableFunc(Order,func(a,b int) int {
+		A,B := p_Axis.keys[a],p_Axis.keys[b]
+		if !A.IsValid() || !B.IsValid() {
+			return boolInt(A.IsValid()) - boolInt(B.IsValid())
+		} // END if
+		return p_Compare(A,B)
+	})
+	return Order
+} // END order
+
+// Add a value to its cell and the totals
+func (p_Matrix *t_PivotMatrix) add(p_Row, p_Column reflect.Value, p_RowLabel, p_ColumnLabel string, p_Value reflect.Value) {
+	Row := p_Matrix.rows.add(p_Row,p_RowLabel)
+	Column := p_Matrix.columns.add(p_Column,p_ColumnLabel)
+	if p_Matrix.total == nil {
+		p_Matrix.total = parseAggregate(p_Matrix.pivot.Aggregate,p_Matrix.tag.Format)
+		p_Matrix.total.style = p_Matrix.style
+	} // END if
+	for len(p_Matrix.rowTotals) <= Row {
+		p_Matrix.rowTotals = append(p_Matrix.rowTotals,p_Matrix.total.empty())
+	} // END for
+	for len(p_Matrix.colTotals) <= Column {
+		p_Matrix.colTotals = append(p_Matrix.colTotals,p_Matrix.total.empty())
+	} // END for
+	Cell,ok := p_Matrix.cells[[2]int{Row,Column}]
+	if !ok {
+		Cell = p_Matrix.total.empty()
+		p_Matrix.cells[[2]int{Row,Column}] = Cell
+	} // END if
+	Cell.add(p_Value)
+	p_Matrix.rowTotals[Row].add(p_Value)
+	p_Matrix.colTotals[Column].add(p_Value)
+	p_Matrix.total.add(p_Value)
+} // END add
+
+// Read the rows of a []struct or []*struct
+func (p_Matrix *t_PivotMatrix) readSlice(p_HTML *T_HTML, p_Data reflect.Value) {
+	var Meta *t_StructMeta
+	var RowColumn, ColColumn, ValueColumn *t_StructColumn
+	for index := 0; index < p_Data.Len(); index++ {
+		if p_HTML.Cancelled() { break } // client is gone
+		Row := derefValue(p_Data.Index(index))
+		if !Row.IsValid() { continue }
+		if Row.Kind() != reflect.Struct { panic(fmt.Sprintf("Unknown datatype used in Pivot: %s",p_Data.Type())) }
+		if Meta == nil {
+			Meta = structMeta(Row.Type())
+			RowColumn = Meta.column(p_Matrix.pivot.RowField)
+			ColColumn = Meta.column(p_Matrix.pivot.ColumnField)
+			ValueColumn = Meta.column(p_Matrix.pivot.ValueField)
+			if RowColumn == nil || ColColumn == nil || (ValueColumn == nil && p_Matrix.pivot.ValueField != "") {
+				panic(fmt.Sprintf("Pivot: unknown field in %s",Row.Type()))
+			} // END if
+			if ValueColumn != nil {
+				p_Matrix.tag, p_Matrix.style = ValueColumn.Tag, ValueColumn.DataStyle
+			} // END if
+		} // END if
+		Value := reflect.ValueOf(1) // count the rows
+		if ValueColumn != nil {
+			Value = ValueColumn.value(Row)
+		} // END if
+		RowKey, ColKey := RowColumn.value(Row), ColColumn.value(Row)
+		p_Matrix.add(RowKey,ColKey,formatValue(RowKey,RowColumn.Tag),formatValue(ColKey,ColColumn.Tag),Value)
+	} // END for
+} // END readSlice
+
+// Read a map[K1]map[K2]V
+func (p_Matrix *t_PivotMatrix) readMap(p_Data reflect.Value) {
+	Iter := p_Data.MapRange()
+	for Iter.Next() {
+		Inner := derefValue(Iter.Value())
+		if !Inner.IsValid() { continue }
+		if Inner.Kind() != reflect.Map { panic(fmt.Sprintf("Unknown datatype used in Pivot: %s",p_Data.Type())) }
+		RowLabel := formatValue(Iter.Key(),t_HtmlTag{})
+		InnerIter := Inner.MapRange()
+		for InnerIter.Next() {
+			p_Matrix.add(Iter.Key(),InnerIter.Key(),RowLabel,formatValue(InnerIter.Key(),t_HtmlTag{}),InnerIter.Value())
+		} // END for
+	} // END for
+} // END readMap
+
+// Read the result set of an SQL query
+func (p_Matrix *t_PivotMatrix) readSqlRows(p_HTML *T_HTML, p_DataRows *sql.Rows) {
+	ColumnNames, err := p_DataRows.Columns()
+	if err != nil {
+		p_HTML.setError(err)
+		return
+	} // END if
+	RowIndex := slices.Index(ColumnNames,p_Matrix.pivot.RowField)
+	ColIndex := slices.Index(ColumnNames,p_Matrix.pivot.ColumnField)
+	ValueIndex := slices.Index(ColumnNames,p_Matrix.pivot.ValueField)
+	if RowIndex < 0 || ColIndex < 0 || (ValueIndex < 0 && p_Matrix.pivot.ValueField != "") {
+		panic(fmt.Sprintf("Pivot: unknown column in %v",ColumnNames))
+	} // END if
+
+	RowPointers := make([]any,len(ColumnNames))
+	RowValues   := make([]any,len(ColumnNames))
+	for index := range RowValues {
+		RowPointers[index] = &RowValues[index]
+	} // END for index
+	for p_DataRows.Next() {
+		if p_HTML.Cancelled() { break } // client is gone
+		if err := p_DataRows.Scan(RowPointers...); err != nil {
+			p_HTML.setError(err)
+			break
+		} // END if
+		Value := reflect.ValueOf(1) // count the rows
+		if ValueIndex >= 0 {
+			Value = sqlValue(reflect.ValueOf(RowValues[ValueIndex]))
+		} // END if
+		RowKey, ColKey := sqlValue(reflect.ValueOf(RowValues[RowIndex])), sqlValue(reflect.ValueOf(RowValues[ColIndex]))
+		p_Matrix.add(RowKey,ColKey,groupText(RowKey),groupText(ColKey),Value)
+	} // END for
+	if err := p_DataRows.Err(); err != nil {
+		p_HTML.setError(err)
+	} // END if
+} // END readSqlRows
+
+// Append the pivot table
+func (p_Matrix *t_PivotMatrix) render(p_HTML *T_HTML, p_TrClass, p_ThClass, p_TdClass string) *T_HTML {
+	Pivot := p_Matrix.pivot
+	Rows := p_Matrix.rows.order(Pivot.RowCompare)
+	Columns := p_Matrix.columns.order(Pivot.ColumnCompare)
+	Corner := Pivot.RowHeader
+	if Corner == "" {
+		Corner = "&nbsp;"
+	} // END if
+
+	p_HTML.headerStart()
+	p_HTML.TheadOpen().helper_TrOpen(p_TrClass).helper_Th(Corner,p_ThClass,"","")
+	for _,Column := range Columns {
+		p_HTML.helper_Tag("th",p_Matrix.columns.labels[Column],p_ThClass,"","","scope","col")
+	} // END for
+	if Pivot.RowTotals {
+		p_HTML.helper_Tag("th",Pivot.TotalLabel,p_ThClass,"total","","scope","col")
+	} // END if
+	p_HTML.TagCloseTop().TagCloseTop() // tr, thead
+
+	p_HTML.TbodyOpen()
+	for _,Row := range Rows {
+		if p_HTML.Cancelled() { break } // client is gone
+		p_HTML.helper_TrOpen(p_TrClass).helper_Tag("th",p_Matrix.rows.labels[Row],p_ThClass,"","","scope","row")
+		for _,Column := range Columns {
+			if Cell,ok := p_Matrix.cells[[2]int{Row,Column}]; ok {
+				p_HTML.helper_Td(Cell.result(),p_TdClass,"",p_Matrix.style)
+			} else {
+				p_HTML.helper_Td(Pivot.Empty,p_TdClass,"",p_Matrix.style)
+			} // END if
+		} // END for
+		if Pivot.RowTotals {
+			p_HTML.helper_Td(p_Matrix.rowTotals[Row].result(),p_TdClass,"total",p_Matrix.style)
+		} // END if
+		p_HTML.helper_TrClose()
+	} // END for
+	p_HTML.TagCloseTop() // tbody
+
+	if Pivot.ColumnTotals {
+		p_HTML.TfootOpen().helper_TrOpen(p_TrClass).helper_Tag("th",Pivot.TotalLabel,p_ThClass,"total","","scope","row")
+		for _,Column := range Columns {
+			p_HTML.helper_Td(p_Matrix.colTotals[Column].result(),p_TdClass,"total",p_Matrix.style)
+		} // END for
+		if Pivot.RowTotals {
+			Total := Pivot.Empty
+			if p_Matrix.total != nil {
+				Total = p_Matrix.total.result()
+			} // END if
+			p_HTML.helper_Td(Total,p_TdClass,"total",p_Matrix.style)
+		} // END if
+		p_HTML.TagCloseTop().TagCloseTop() // tr, tfoot
+	} // END if
+	return p_HTML
+} // END render
@@ -0,0 +1,243 @@
+package UTL_HTML
+//
+// UTL_HTML_Select
+// Version: $Id$
+//
+import (
+	"fmt"
+	"slices"
+	"reflect"
+	"database/sql"
+)
+
+// *************************************************************************************
+// Select menus: the options come from maps, ordered slices of pairs, slices of structs
+// or the result set of an SQL query. Options with a group are put into <optgroup>s,
+// several values can be selected, single options disabled, and a placeholder option
+// without value comes first.
+//
+//  map[K]V              → the keys are the labels, the values the values of the options
+//  []T_Option           → value, label and group
+//  [][2]string          → value and label
+//  []T / []*T           → the values are value and label
+//  []struct / []*struct → the fields KeyField, LabelField and GroupField
+//  *sql.Rows            → the columns KeyField, LabelField and GroupField, default: the first column is the value, the second the label
+
+// Definition of a select menu
+type T_Select struct {
+	MenuClass   string        // CSS classname of the <select>
+	ItemClass   string        // CSS classname of the <option>s
+	Selected    []string      // values of the selected options
+	Disabled    []string      // values of the disabled options
+	Multiple    bool          // several options can be selected
+	Placeholder string        // label of a first option with the value "", e.g. "Please choose", empty: no placeholder
+	KeyField    string        // structs and *sql.Rows: field or column with the values
+	LabelField  string        // structs and *sql.Rows: field or column with the labels, default: KeyField
+	GroupField  string        // structs and *sql.Rows: field or column with the <optgroup> labels, empty: no groups
+	Compare     t_CompareFunc // sort order, maps by key and slices of values by value, the others by label; default: maps by CmpAsc, the others unsorted
+} // END T_Select
+
+// An option of a select menu
+type T_Option struct {
+	Value string
+	Label string
+	Group string // label of the <optgroup>, empty: no group
+} // END T_Option
+
+// Append a select menu. Options without group come first, then the <optgroup>s in the order of
+// their first option. With FormErrors, the values the user typed are selected instead of p_Menu.Selected.
+func (p_HTML *T_HTML) Select(p_FieldName string, p_Menu T_Select, p_MenuItems any, p_Attributes ...string) *T_HTML {
+	Items,err := selectItems(p_MenuItems,&p_Menu,"Select")
+	if err != nil {
+		p_HTML.setError(err) // the options read so far are shown
+	} // END if
+	if Typed := p_HTML.formTyped(p_FieldName); Typed != nil {
+		p_Menu.Selected = Typed
+	} // END if
+
+	Arguments := []string{}
+	appendAttribute("class",p_Menu.MenuClass,&Arguments)
+	appendAttribute("name",p_FieldName,&Arguments)
+	if p_Menu.Multiple {
+		Arguments = append(Arguments,"multiple","multiple")
+	} // END if
+	Arguments = append(Arguments,p_Attributes...)
+	p_HTML.TagOpen("select",p_HTML.formInvalid(p_FieldName,Arguments)...)
+	Depth := len(p_HTML.tagStack)
+	if p_Menu.Placeholder != "" {
+		p_HTML.tagClosed("option",p_Menu.Placeholder,"value","")
+	} // END if
+
+	Groups := []string{""}
+	for _,Item := range Items {
+		if !slices.Contains(Groups,Item.group) {
+			Groups = append(Groups,Item.group)
+		} // END if
+	} // END for
+	for _,Group := range Groups {
+		if Group != "" {
+			p_HTML.TagOpen("optgroup","label",Group)
+		} // END if
+		for _,Item := range Items {
+			if Item.group != Group { continue }
+			Arguments := []string{"value",Item.value}
+			appendAttribute("class",p_Menu.ItemClass,&Arguments)
+			if slices.Contains(p_Menu.Selected,Item.value) {
+				Arguments = append(Arguments,"selected","selected")
+			} // END if
+			if slices.Contains(p_Menu.Disabled,Item.value) {
+				Arguments = append(Arguments,"disabled","disabled")
+			} // END if
+			p_HTML.tagClosed("option",Item.label,Arguments...)
+		} // END for
+		if Group != "" {
+			p_HTML.TagCloseTop() // optgroup
+		} // END if
+	} // END for
+	for len(p_HTML.tagStack) >= Depth {
+		p_HTML.TagCloseTop() // select
+	} // END for
+	return p_HTML.formMessage(p_FieldName)
+} // END Select
+
+// Return a select menu as a string, errors of *sql.Rows panic
+func Select(p_FieldName string, p_Menu T_Select, p_MenuItems any, p_Attributes ...string) string {
+	HTML := New(GC_DocTypeNONE,0x00).Select(p_FieldName,p_Menu,p_MenuItems,p_Attributes...)
+	if err := HTML.Err(); err != nil { panic(err) }
+	return HTML.String()
+} // END Select
+
+// Append a select menu with the menu-items of a map - the keys are the labels - or of the other sources of Select,
+// with the option of the value p_DefaultValue selected. p_Attributes are attribute/value pairs, the values are formatted with fmt.Sprint.
+func (p_HTML *T_HTML) SelectMenu(p_FieldName, p_MenuClassName, p_ItemClassName, p_DefaultValue string, p_CompareFunc t_CompareFunc, p_MenuItems any, p_Attributes ...any) *T_HTML {
+	Menu := T_Select{MenuClass: p_MenuClassName, ItemClass: p_ItemClassName, Compare: p_CompareFunc}
+	if p_DefaultValue != "" {
+		Menu.Selected = []string{p_DefaultValue}
+	} // END if
+	Arguments := make([]string,0,len(p_Attributes))
+	for _,Attribute := range p_Attributes {
+		Arguments = append(Arguments,fmt.Sprint(Attribute))
+	} // END for
+	return p_HTML.Select(p_FieldName,Menu,p_MenuItems,Arguments...)
+} // END SelectMenu
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+var v_OptionType = reflect.TypeFor[T_Option]()
+
+// Read the options of the menu-items, sorted by p_Menu.Compare
+func selectItems(p_Items any, p_Menu *T_Select, p_Caller string) ([]t_FormOption, error) {
+	if Rows,ok := p_Items.(*sql.Rows); ok {
+		Items,err := selectSqlRows(Rows,p_Menu,p_Caller)
+		return selectSort(Items,p_Menu.Compare), err
+	} // END if
+	Items := reflect.ValueOf(p_Items)
+	Options := []t_FormOption{}
+	switch Items.Kind() {
+		case reflect.Map: {
+			Iter := Items.MapRange()
+			for Iter.Next() {
+				Options = append(Options,t_FormOption{value: groupText(Iter.Value()), label: groupText(Iter.Key()), sort: Iter.Key()})
+			} // END for
+			if p_Menu.Compare == nil {
+				return selectSort(Options,CmpAsc), nil
+			} // END if
+		} // END case
+		case reflect.Slice, reflect.Array: {
+			var Meta *t_StructMeta
+			var KeyColumn, LabelColumn, GroupColumn *t_StructColumn
+			for index := 0; index < Items.Len(); index++ {
+				Item := derefValue(Items.Index(index))
+				switch {
+					case !Item.IsValid(): continue
+					case Item.Type() == v_OptionType: {
+						Option := Item.Interface().(T_Option)
+						Options = append(Options,t_FormOption{value: Option.Value, label: Option.Label, group: Option.Group, sort: reflect.ValueOf(Option.Label)})
+					} // END case
+					case Item.Kind() == reflect.Array && Item.Len() == 2 && Item.Type().Elem().Kind() == reflect.String: {
+						Options = append(Options,t_FormOption{value: Item.Index(0).String(), label: Item.Index(1).String(), sort: Item.Index(1)})
+					} // END case
+					case Item.Kind() == reflect.Struct && Item.Type() != v_TimeType: {
+						if Meta == nil {
+							Meta = structMeta(Item.Type())
+							KeyColumn, LabelColumn = Meta.column(p_Menu.KeyField), Meta.column(selectLabel(p_Menu))
+							GroupColumn = Meta.column(p_Menu.GroupField)
+							if KeyColumn == nil || LabelColumn == nil || (GroupColumn == nil && p_Menu.GroupField != "") {
+								panic(fmt.Sprintf("%s: unknown field in %s",p_Caller,Item.Type()))
+							} // END if
+						} // END if
+						Option := t_FormOption{value: formValue(KeyColumn.value(Item),"select",KeyColumn.Tag), label: formatValue(LabelColumn.value(Item),LabelColumn.Tag)}
+						if GroupColumn != nil {
+							Option.group = formatValue(GroupColumn.value(Item),GroupColumn.Tag)
+						} // END if
+						Option.sort = reflect.ValueOf(Option.label)
+						Options = append(Options,Option)
+					} // END case
+					default: {
+						Options = append(Options,t_FormOption{value: groupText(Item), label: groupText(Item), sort: Item})
+					} // END default
+				} // END switch
+			} // END for
+		} // END case
+		default: panic(fmt.Sprintf("Unknown datatype used in %s: %T",p_Caller,p_Items))
+	} // END switch
+	return selectSort(Options,p_Menu.Compare), nil
+} // END selectItems
+
+// Read the options of a result set
+func selectSqlRows(p_DataRows *sql.Rows, p_Menu *T_Select, p_Caller string) ([]t_FormOption, error) {
+	ColumnNames, err := p_DataRows.Columns()
+	if err != nil {
+		return nil, err
+	} // END if
+	KeyIndex, LabelIndex, GroupIndex := 0, 1, -1
+	if p_Menu.KeyField != "" {
+		KeyIndex, LabelIndex = slices.Index(ColumnNames,p_Menu.KeyField), slices.Index(ColumnNames,selectLabel(p_Menu))
+	} else if p_Menu.LabelField != "" {
+		LabelIndex = slices.Index(ColumnNames,p_Menu.LabelField)
+	} else if len(ColumnNames) < 2 {
+		LabelIndex = 0 // one column: value and label
+	} // END if
+	if p_Menu.GroupField != "" {
+		GroupIndex = slices.Index(ColumnNames,p_Menu.GroupField)
+	} // END if
+	if KeyIndex < 0 || LabelIndex < 0 || (GroupIndex < 0 && p_Menu.GroupField != "") {
+		panic(fmt.Sprintf("%s: unknown column in %v",p_Caller,ColumnNames))
+	} // END if
+
+	RowPointers := make([]any,len(ColumnNames))
+	RowValues   := make([]any,len(ColumnNames))
+	for index := range RowValues {
+		RowPointers[index] = &RowValues[index]
+	} // END for index
+	Options := []t_FormOption{}
+	for p_DataRows.Next() {
+		if err := p_DataRows.Scan(RowPointers...); err != nil {
+			return Options, err
+		} // END if
+		Option := t_FormOption{value: groupText(sqlValue(reflect.ValueOf(RowValues[KeyIndex]))), label: groupText(sqlValue(reflect.ValueOf(RowValues[LabelIndex])))}
+		if GroupIndex >= 0 {
+			Option.group = groupText(sqlValue(reflect.ValueOf(RowValues[GroupIndex])))
+		} // END if
+		Option.sort = reflect.ValueOf(Option.label)
+		Options = append(Options,Option)
+	} // END for
+	return Options, p_DataRows.Err()
+} // END selectSqlRows
+
+// Field or column with the labels
+func selectLabel(p_Menu *T_Select) string {
+	if p_Menu.LabelField != "" {
+		return p_Menu.LabelField
+	} // END if
+	return p_Menu.KeyField
+} // END selectLabel
+
+// Sort the options, nil: keep the order
+func selectSort(p_Options []t_FormOption, p_Compare t_CompareFunc) []t_FormOption {
+	if p_Compare != nil {
+		slices.SortStableFunc(p_Options,func(a, b t_FormOption) int { return p_Compare(a.sort,b.sort) })
+	} // END if
+	return p_Options
+} // END selectSort
@@ -0,0 +1,180 @@
+package UTL_HTML
+//
+// UTL_HTML_Sort
+// Version: $Id$
+//
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"reflect"
+	"net/url"
+)
+
+const (
+	GC_ParamSort string = "sort" // request parameter with the name of the sort column
+	GC_ParamDir  string = "dir"  // request parameter with the sort direction: asc or desc
+) // END const
+
+// Turn on the sortable-table mode: The header cells of TrTh, TrThStruct and TrThSqlRows become links
+// carrying the sort column and direction as request parameters, TrTdSlice and TrTdMap sort their rows
+// accordingly. The parameters are taken from the map of ReadReqParameter, the parameters named in
+// p_KeepParams are carried along in the links.
+func (p_HTML *T_HTML) Sortable(p_Parameter map[string]string, p_KeepParams ...string) *T_HTML {
+	p_HTML.sortActive  = true
+	p_HTML.sortColumn  = p_Parameter[GC_ParamSort]
+	p_HTML.sortDesc    = strings.EqualFold(p_Parameter[GC_ParamDir],"desc")
+	p_HTML.columnNames = make([]string,0,10)
+	p_HTML.keepParams(p_Parameter,p_KeepParams...)
+	return p_HTML
+} // END Sortable
+
+// Turn off the sortable-table mode, e.g. before a second table in the same document
+func (p_HTML *T_HTML) SortableEnd() *T_HTML {
+	p_HTML.sortActive  = false
+	p_HTML.sortColumn  = ""
+	p_HTML.sortDesc    = false
+	p_HTML.columnNames = nil
+	return p_HTML
+} // END SortableEnd
+
+// Return the query with an ORDER BY clause for the requested sort column.
+// Only columns listed in p_Columns are accepted, otherwise the query is returned unchanged.
+// The original query is wrapped as sub-query; it should not contain an ORDER BY clause of its own,
+// the order of a sub-query is not kept by SQL and some databases refuse it. For pages use LimitQuery
+// with SortOrder, which puts ORDER BY and LIMIT on the same SELECT.
+func (p_HTML *T_HTML) OrderBy(p_Query string, p_Columns ...string) string {
+	Order := p_HTML.SortOrder("",p_Columns...)
+	if Order == "" {
+		return p_Query
+	} // END if
+	return fmt.Sprintf("SELECT * FROM (%s) t_sorted ORDER BY %s",p_Query,Order)
+} // END OrderBy
+
+// Return the sort order for an ORDER BY clause: the requested sort column with ASC or DESC, if it is
+// listed in p_Columns, otherwise p_Default, e.g. "Breed" or "Class, Breed"; see LimitQuery.
+func (p_HTML *T_HTML) SortOrder(p_Default string, p_Columns ...string) string {
+	if !p_HTML.sortActive || !slices.Contains(p_Columns,p_HTML.sortColumn) {
+		return p_Default
+	} // END if
+	if p_HTML.sortDesc {
+		return p_HTML.sortColumn + " DESC"
+	} // END if
+	return p_HTML.sortColumn + " ASC"
+} // END SortOrder
+
+// -------------------------------------------------------------------
+// not exported helper functions
+
+// Remember the request parameters to be kept in generated links
+func (p_HTML *T_HTML) keepParams(p_Parameter map[string]string, p_KeepParams ...string) {
+	if p_HTML.linkParams == nil {
+		p_HTML.linkParams = make(map[string]string)
+	} // END if
+	for _,Name := range p_KeepParams {
+		if Value,ok := p_Parameter[Name]; ok {
+			p_HTML.linkParams[Name] = Value
+		} // END if
+	} // END for
+} // END keepParams
+
+// Build the query-string of a link from the kept parameters, the current sort-state and the name/value pairs
+func (p_HTML *T_HTML) linkURL(p_Pairs ...string) string {
+	Values := url.Values{}
+	for Name,Value := range p_HTML.linkParams {
+		Values.Set(Name,Value)
+	} // END for
+	if p_HTML.sortActive && p_HTML.sortColumn != "" {
+		Values.Set(GC_ParamSort,p_HTML.sortColumn)
+		if p_HTML.sortDesc {
+			Values.Set(GC_ParamDir,"desc")
+		} else {
+			Values.Set(GC_ParamDir,"asc")
+		} // END if
+	} // END if
+	for index := 0; index+1 < len(p_Pairs); index += 2 {
+		Values.Set(p_Pairs[index],p_Pairs[index+1])
+	} // END for
+	return "?" + strings.ReplaceAll(Values.Encode(),"&","&amp;")
+} // END linkURL
+
+// Return the position of the sort column in the last sortable header-row, -1 if not sorted
+func (p_HTML *T_HTML) sortIndex() int {
+	if !p_HTML.sortActive || p_HTML.sortColumn == "" {
+		return -1
+	} // END if
+	return slices.Index(p_HTML.columnNames,p_HTML.sortColumn)
+} // END sortIndex
+
+// Compare two values in the current sort direction, nil values first
+func (p_HTML *T_HTML) sortCompare(a,b reflect.Value) (Result int) {
+	a,b = derefValue(a),derefValue(b)
+	switch {
+		case !a.IsValid() || !b.IsValid(): Result = cmp.Compare(boolInt(a.IsValid()),boolInt(b.IsValid()))
+		case a.Kind() != b.Kind(): Result = cmp.Compare(fmt.Sprint(a.Interface()),fmt.Sprint(b.Interface()))
+		case a.Kind() == reflect.Bool: Result = cmp.Compare(boolInt(a.Bool()),boolInt(b.Bool()))
+		default: Result = CmpAsc(a,b)
+	} // END switch
+	if p_HTML.sortDesc {
+		return -Result
+	} // END if
+	return Result
+} // END sortCompare
+
+// Return the rows of a slice, sorted by the group and the sort columns if requested. The slice itself is not changed.
+func (p_HTML *T_HTML) sortRows(p_DataRows reflect.Value) []reflect.Value {
+	Rows := make([]reflect.Value,p_DataRows.Len())
+	for index := range Rows {
+		Rows[index] = p_DataRows.Index(index)
+	} // END for
+	if Column := p_HTML.sortIndex(); Column >= 0 {
+		slices.SortStableFunc(Rows,func(a,b reflect.Value) int {
+			return p_HTML.sortCompare(columnValue(a,Column),columnValue(b,Column))
+		})
+	} // END if
+	p_HTML.groupSort(Rows) // the groups must be consecutive, sorted by the sort column within the groups
+	return pageSlice(p_HTML,Rows)
+} // END sortRows
+
+// Return the value of the n-th table-column of a row: n-th displayed field of a struct or n-th element of a slice.
+// Rows of other types consist of a single column.
+func columnValue(p_Row reflect.Value, p_Column int) reflect.Value {
+	Row := derefValue(p_Row)
+	switch Row.Kind() {
+		case reflect.Struct: {
+			if Columns := structMeta(Row.Type()).Columns; p_Column < len(Columns) {
+				return Columns[p_Column].value(Row)
+			} // END if
+		} // END case
+		case reflect.Slice, reflect.Array: {
+			if p_Column < Row.Len() {
+				return Row.Index(p_Column)
+			} // END if
+		} // END case
+		default: {
+			if p_Column == 0 {
+				return Row
+			} // END if
+		} // END default
+	} // END switch
+	return reflect.Value{}
+} // END columnValue
+
+// Dereference pointers and interfaces, nil results in an invalid reflect.Value
+func derefValue(p_Value reflect.Value) reflect.Value {
+	for p_Value.IsValid() && (p_Value.Kind() == reflect.Ptr || p_Value.Kind() == reflect.Interface) {
+		if p_Value.IsNil() {
+			return reflect.Value{}
+		} // END if
+		p_Value = p_Value.Elem()
+	} // END for
+	return p_Value
+} // END derefValue
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	} // END if
+	return 0
+} // END boolInt
@@ -0,0 +1,293 @@
+package UTL_HTML
+//
+// UTL_HTML_Sql
+// Version: $Id$
+//
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"reflect"
+	"time"
+	"database/sql"
+	"database/sql/driver"
+)
+
+// *************************************************************************************
+// Values of database/sql: Drivers return text columns as []byte, NULL as nil and
+// DATE/TIMESTAMP columns as time.Time, structs and slices may contain sql.NullString & Co.
+// The values are converted before they are printed, aggregated or passed to the rules.
+// The column types of a result set decide about alignment and formatting: numbers are
+// right-aligned, DECIMAL columns keep their scale, dates and times get an ISO layout.
+
+// Layouts for time.Time values of SQL result sets, by database type
+const (
+	GC_SqlDateLayout     string = "2006-01-02"
+	GC_SqlTimeLayout     string = "15:04:05"
+	GC_SqlDateTimeLayout string = "2006-01-02 15:04:05"
+) // END const
+
+// Default texts of QueryTable
+const (
+	GC_QueryTruncated string = "Only the first {max} rows are shown."
+	GC_QueryFailed    string = "The query failed."
+) // END const
+
+// Source of QueryTable: *sql.DB, *sql.Conn or *sql.Tx
+type T_Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+} // END T_Queryer
+
+// Layout of a table created by QueryTable. The texts may contain the placeholders {rows}, {max}, {elapsed} and {error}.
+type T_QueryTable struct {
+	Caption      string // caption of the table, empty: no caption
+	MaxRows      int    // maximum number of data-rows, 0: no limit
+	Truncated    string // notice below the rows when the result has more than MaxRows rows, default GC_QueryTruncated
+	Failed       string // content of the row shown instead of the data when the query fails, default GC_QueryFailed
+	TableClass   string // CSS classname for <table>
+	CaptionClass string // CSS classname for <caption>
+	TrClass      string // CSS classname for <tr>
+	ThClass      string // CSS classname for <th>
+	TdClass      string // CSS classname for <td>
+} // END T_QueryTable
+
+// Result of the last QueryTable
+type T_QueryResult struct {
+	Rows      int           // number of data-rows shown
+	MaxRows   int           // MaxRows of the table
+	Truncated bool          // the result has more rows than shown
+	Elapsed   time.Duration // time for the query and reading the rows
+	Err       error         // error of the query or of reading the rows, nil: none
+} // END T_QueryResult
+
+// Run a query and append the complete table: caption, header-row from the column names and
+// data-rows like TrTdSqlRows. At most MaxRows rows are shown, followed by a notice if there
+// are more. Errors are shown as a row with the text Failed and are returned by Err() and QueryResult().
+//  - ctx - context of the query, usually r.Context()
+//  - p_DB - *sql.DB, *sql.Conn or *sql.Tx
+//  - p_Table - caption, row limit, texts and CSS classnames
+//  - p_Query, p_Args - query and arguments as for sql.DB.QueryContext
+//
+// Placeholders in the caption are replaced after the rows have been read, so progressive
+// flushing waits until the table is complete.
+func (p_HTML *T_HTML) QueryTable(ctx context.Context, p_DB T_Queryer, p_Table T_QueryTable, p_Query string, p_Args ...any) *T_HTML {
+	if p_Table.Truncated == "" {
+		p_Table.Truncated = GC_QueryTruncated
+	} // END if
+	if p_Table.Failed == "" {
+		p_Table.Failed = GC_QueryFailed
+	} // END if
+	Attributes := []string{}
+	appendAttribute("class",p_Table.TableClass,&Attributes)
+	p_HTML.TableOpen(Attributes...)
+	Depth := len(p_HTML.tagStack)
+	CaptionOffset := -1
+	if strings.Contains(p_Table.Caption,"{") {
+		CaptionOffset = p_HTML.content.Len() // the caption is inserted when the numbers are known
+		p_HTML.captionHold = true
+	} else if p_Table.Caption != "" {
+		p_HTML.helper_Caption(p_Table.Caption,p_Table.CaptionClass)
+	} // END if
+
+	Result := T_QueryResult{MaxRows: p_Table.MaxRows}
+	Start := time.Now()
+	Rows,err := p_DB.QueryContext(ctx,p_Query,p_Args...)
+	NumColumns := 1
+	var ColumnNames []string
+	if err == nil {
+		defer Rows.Close()
+		ColumnNames,err = Rows.Columns()
+	} // END if
+	if err == nil {
+		NumColumns = max(len(ColumnNames),1)
+		p_HTML.TheadOpen().TrThSqlRows(p_Table.TrClass,p_Table.ThClass,Rows).TagCloseTop()
+		Before := p_HTML.err
+		p_HTML.err = nil
+		p_HTML.sqlMax = p_Table.MaxRows
+		p_HTML.TbodyOpen().TrTdSqlRows(p_Table.TrClass,p_Table.TdClass,Rows).TagCloseTop()
+		p_HTML.sqlMax = 0
+		Result.Rows, Result.Truncated = p_HTML.sqlRows, p_HTML.sqlMore
+		err = p_HTML.err
+		p_HTML.err = Before
+	} // END if
+	Result.Elapsed = time.Since(Start)
+	Result.Err = err
+	p_HTML.queryResult = Result
+	if err != nil {
+		p_HTML.setError(err)
+		p_HTML.TbodyOpen().
+		       helper_TrOpen(strings.TrimSpace(p_Table.TrClass+" error")).
+		         helper_Tag("td",Result.Expand(p_Table.Failed),p_Table.TdClass,"","","colspan",strconv.Itoa(NumColumns)).
+		       helper_TrClose().
+		       TagCloseTop()
+	} else if Result.Truncated {
+		p_HTML.TfootOpen().
+		       helper_TrOpen(strings.TrimSpace(p_Table.TrClass+" truncated")).
+		         helper_Tag("td",Result.Expand(p_Table.Truncated),p_Table.TdClass,"","","colspan",strconv.Itoa(NumColumns)).
+		       helper_TrClose().
+		       TagCloseTop()
+	} // END if
+
+	if CaptionOffset >= 0 {
+		p_HTML.captionHold = false
+		Tail := p_HTML.content.Len()
+		p_HTML.helper_Caption(Result.Expand(p_Table.Caption),p_Table.CaptionClass)
+		Caption := bytes.Clone(p_HTML.content.Bytes()[Tail:])
+		p_HTML.content.Truncate(Tail)
+		p_HTML.groupInsert(CaptionOffset,string(Caption))
+	} // END if
+	for len(p_HTML.tagStack) >= Depth {
+		p_HTML.TagCloseTop() // table
+	} // END for
+	return p_HTML
+} // END QueryTable
+
+// Return the result of the last QueryTable: number of rows, elapsed time and error
+func (p_HTML *T_HTML) QueryResult() T_QueryResult {
+	return p_HTML.queryResult
+} // END QueryResult
+
+// Replace the placeholders {rows}, {max}, {elapsed} and {error} in a text,
+// e.g. v_Doc.P("",v_Doc.QueryResult().Expand("{rows} rows in {elapsed}"))
+func (p_Result T_QueryResult) Expand(p_Text string) string {
+	Error := ""
+	if p_Result.Err != nil {
+		Error = p_Result.Err.Error()
+	} // END if
+	Elapsed := p_Result.Elapsed.Round(time.Millisecond)
+	if p_Result.Elapsed < time.Millisecond {
+		Elapsed = p_Result.Elapsed.Round(time.Microsecond)
+	} // END if
+	return strings.NewReplacer(
+		"{rows}",strconv.Itoa(p_Result.Rows),
+		"{max}",strconv.Itoa(p_Result.MaxRows),
+		"{elapsed}",Elapsed.String(),
+		"{error}",Error,
+	).Replace(p_Text)
+} // END Expand
+
+// Return the first error of the table functions, e.g. from sql.Rows.Err(), nil if there was none.
+// The table functions return *T_HTML for chaining, so errors are recorded and rendering stops.
+func (p_HTML *T_HTML) Err() error {
+	return p_HTML.err
+} // END Err
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// Formatting of a column of an SQL result set, derived from its database type
+type t_SqlColumn struct {
+	tag   t_HtmlTag // Layout for dates and times
+	style string    // style attribute, right-aligned numbers
+	scale int       // number of decimals of DECIMAL and NUMERIC columns, -1: unknown
+} // END t_SqlColumn
+
+// Database types of numbers, without size and UNSIGNED
+var sqlNumberTypes = map[string]bool{
+	"INTEGER": true, "INT": true, "INT2": true, "INT4": true, "INT8": true,
+	"TINYINT": true, "SMALLINT": true, "MEDIUMINT": true, "BIGINT": true,
+	"SERIAL": true, "BIGSERIAL": true, "SMALLSERIAL": true,
+	"DECIMAL": true, "NUMERIC": true, "NUMBER": true, "MONEY": true,
+	"REAL": true, "FLOAT": true, "FLOAT4": true, "FLOAT8": true, "DOUBLE": true, "DOUBLE PRECISION": true,
+} // END sqlNumberTypes
+
+// Caption with an optional class
+func (p_HTML *T_HTML) helper_Caption(p_Content, p_Class string) *T_HTML {
+	if p_Class != "" {
+		return p_HTML.Caption(p_Content,"class",p_Class)
+	} // END if
+	return p_HTML.Caption(p_Content)
+} // END helper_Caption
+
+// Remember the first error, the table functions stop at an error
+func (p_HTML *T_HTML) setError(p_Error error) {
+	if p_HTML.err == nil {
+		p_HTML.err = p_Error
+	} // END if
+} // END setError
+
+// Formatting of the columns of a result set, without column types all columns are formatted by their values
+func sqlColumns(p_DataRows *sql.Rows, p_NumColumns int) []t_SqlColumn {
+	Columns := make([]t_SqlColumn,p_NumColumns)
+	Types,err := p_DataRows.ColumnTypes()
+	if err != nil || len(Types) != p_NumColumns {
+		for index := range Columns {
+			Columns[index].scale = -1
+		} // END for
+		return Columns
+	} // END if
+	for index,Type := range Types {
+		Columns[index].scale = -1
+		Name := strings.ToUpper(strings.TrimSpace(Type.DatabaseTypeName()))
+		Size := ""
+		if Position := strings.IndexByte(Name,'('); Position >= 0 {
+			Name,Size = strings.TrimSpace(Name[:Position]),strings.Trim(Name[Position:],"() ") // VARCHAR(20), DECIMAL(10,2)
+		} // END if
+		Name = strings.TrimSpace(strings.TrimSuffix(Name,"UNSIGNED"))
+		switch Name {
+			case "DATE": Columns[index].tag.Layout = GC_SqlDateLayout
+			case "TIME": Columns[index].tag.Layout = GC_SqlTimeLayout
+		} // END switch
+		Number := sqlNumberTypes[Name]
+		if Name == "" && Type.ScanType() != nil {
+			_,Number = numericValue(reflect.Zero(Type.ScanType()))
+		} // END if
+		if Number {
+			Columns[index].style = "text-align: right"
+		} // END if
+		if Name == "DECIMAL" || Name == "NUMERIC" || Name == "NUMBER" {
+			if _,Scale,ok := Type.DecimalSize(); ok {
+				Columns[index].scale = int(Scale)
+			} else if _,Scale,ok := strings.Cut(Size,","); ok { // the driver doesn't know the size, but the declared type has it
+				if Scale,err := strconv.Atoi(strings.TrimSpace(Scale)); err == nil {
+					Columns[index].scale = Scale
+				} // END if
+			} // END if
+		} // END if
+	} // END for
+	return Columns
+} // END sqlColumns
+
+// Format a value of a result set: NULL is "&nbsp;", time.Time is formatted by the layout
+// of the column or as date and time, floats of DECIMAL columns get the scale of the column.
+func (p_Column t_SqlColumn) text(p_Value reflect.Value) string {
+	if !p_Value.IsValid() {
+		return "&nbsp;"
+	} // END if
+	switch Value := p_Value.Interface().(type) {
+		case time.Time: {
+			if p_Column.tag.Layout != "" {
+				return Value.Format(p_Column.tag.Layout)
+			} // END if
+			return Value.Format(GC_SqlDateTimeLayout)
+		} // END case
+		case float64: {
+			if p_Column.scale >= 0 {
+				return strconv.FormatFloat(Value,'f',p_Column.scale,64)
+			} // END if
+		} // END case
+	} // END switch
+	return formatValue(p_Value,p_Column.tag)
+} // END text
+
+var valuerType = reflect.TypeFor[driver.Valuer]()
+
+// Convert a value of database/sql into a printable value: sql.Null* and other driver.Valuer
+// are replaced by their value, []byte by a string, NULL by an invalid reflect.Value.
+func sqlValue(p_Value reflect.Value) reflect.Value {
+	Value := derefValue(p_Value)
+	for range 4 { // a Valuer may return a Valuer
+		switch {
+			case !Value.IsValid(): return Value
+			case Value.Kind() == reflect.Slice && Value.Type().Elem().Kind() == reflect.Uint8: return reflect.ValueOf(string(Value.Bytes()))
+			case Value.Type().PkgPath() == "" || !Value.Type().Implements(valuerType): return Value // the common case: predeclared types and no Valuer
+		} // END switch
+		Result,err := Value.Interface().(driver.Valuer).Value()
+		if err != nil || Result == nil {
+			return reflect.Value{}
+		} // END if
+		Value = reflect.ValueOf(Result)
+	} // END for
+	return Value
+} // END sqlValue
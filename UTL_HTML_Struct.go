@@ -0,0 +1,292 @@
+package UTL_HTML
+//
+// UTL_HTML_Struct
+// Version: $Id$
+//
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"sync"
+	"time"
+	"slices"
+	"strings"
+	"reflect"
+	"net/url"
+)
+
+// *************************************************************************************
+// Metadata of a struct{}-type for the table functions: The columns with their headers,
+// classes, styles and formatting. The html struct-tags are analyzed once per type, the
+// result is cached and shared by TrThStruct, TrTdStruct, TrTdSlice and TrTdMap.
+
+type t_StructMeta struct {
+	Columns []t_StructColumn // exported fields without Skip in the struct-tag, ordered by Order
+	Grouped bool             // some columns belong to a group: the header has two rows
+} // END t_StructMeta
+
+type t_StructColumn struct {
+	Index       []int        // index path of the field, see reflect.Value.FieldByIndex
+	Name        string       // name of the field, the path for fields of grouped structs: "Address.City"
+	Header      string       // column header: ColHeader or the name of the field
+	Type        reflect.Type // type of the field
+	Group       string       // header of the group, empty for columns without group
+	Tag         t_HtmlTag    // the analyzed html struct-tag
+	HeaderStyle string       // style attribute of <th>
+	DataStyle   string       // style attribute of <td>
+	link        []t_LinkPart // the Link template, split into literals and fields
+	depth       int          // depth of embedding, to resolve name conflicts like encoding/json
+} // END t_StructColumn
+
+// Part of a Link template: a literal text, a field or the map-key
+type t_LinkPart struct {
+	Literal string
+	Field   []int // index path of the field, nil for literals and the key
+	Key     bool  // {Key}: the value of the key column
+} // END t_LinkPart
+
+const (
+	gc_NestedFlatten string = "flatten" // the fields of the struct become columns of their own
+	gc_NestedGroup   string = "group"   // like flatten, with a group header above the columns
+	gc_NestedString  string = "string"  // the struct is one column, printed with fmt.Sprint
+) // END const
+
+var v_StructMetaCache sync.Map // reflect.Type → *t_StructMeta
+
+var v_StringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+var v_TimeType = reflect.TypeOf(time.Time{})
+
+// Return the metadata of a struct{}-type, safe for concurrent use
+func structMeta(p_Type reflect.Type) *t_StructMeta {
+	if Meta,ok := v_StructMetaCache.Load(p_Type); ok {
+		return Meta.(*t_StructMeta)
+	} // END if
+	Meta,_ := v_StructMetaCache.LoadOrStore(p_Type,buildStructMeta(p_Type))
+	return Meta.(*t_StructMeta)
+} // END structMeta
+
+// Return the column with the name, e.g. "Price" or "Address.City", nil if there is none
+func (p_Meta *t_StructMeta) column(p_Name string) *t_StructColumn {
+	for index := range p_Meta.Columns {
+		if p_Meta.Columns[index].Name == p_Name {
+			return &p_Meta.Columns[index]
+		} // END if
+	} // END for
+	return nil
+} // END column
+
+// Analyze the fields and html struct-tags of a struct{}-type
+// Embedded structs are flattened the way encoding/json does: their fields are promoted, on name
+// conflicts the shallower field wins, conflicting fields of the same depth are dropped.
+// Nested structs become grouped columns, pointers to structs and types containing themselves one column;
+// Nested='flatten|group|string' in the struct-tag overrides this, except for types containing themselves.
+func buildStructMeta(p_Type reflect.Type) *t_StructMeta {
+	Columns := structColumns(p_Type,p_Type,nil,"","",0,[]reflect.Type{p_Type})
+
+	// resolve name conflicts of promoted fields
+	Depth := make(map[string]int)
+	Count := make(map[string]int)
+	for _,Column := range Columns {
+		if Min,ok := Depth[Column.Name]; !ok || Column.depth < Min {
+			Depth[Column.Name] = Column.depth
+			Count[Column.Name] = 0
+		} // END if
+		if Column.depth == Depth[Column.Name] {
+			Count[Column.Name]++
+		} // END if
+	} // END for
+	Meta := &t_StructMeta{Columns: make([]t_StructColumn,0,len(Columns))}
+	for _,Column := range Columns {
+		if Column.depth != Depth[Column.Name] || Count[Column.Name] > 1 { continue }
+		Meta.Columns = append(Meta.Columns,Column)
+		Meta.Grouped = Meta.Grouped || Column.Group != ""
+		if Column.Tag.Aggregate != "" {
+			parseAggregate(Column.Tag.Aggregate,"") // check the definition once, not while rendering
+		} // END if
+		if Column.Tag.Pattern != "" {
+			checkRegexp(Column.Tag.Pattern) // compile the pattern once, not while validating
+		} // END if
+	} // END for
+	return Meta
+} // END buildStructMeta
+
+// Return the columns of one struct level, ordered by Order
+//  - p_Root - the type of the outermost struct, to resolve the fields of Link templates
+//  - p_Index - index path of the struct within the outermost struct
+//  - p_Group - header of the group the columns belong to
+//  - p_Prefix - prefix for the names of the columns of nested structs
+//  - p_Path - the struct types from the outermost struct to p_Type, a type on the path is not expanded again
+func structColumns(p_Root, p_Type reflect.Type, p_Index []int, p_Group, p_Prefix string, p_Depth int, p_Path []reflect.Type) []t_StructColumn {
+	type t_Entry struct {
+		order   int
+		tagged  bool // Order in the struct-tag: comes first on equal positions
+		columns []t_StructColumn
+	} // END t_Entry
+	Entries := make([]t_Entry,0,p_Type.NumField())
+	for index := 0; index < p_Type.NumField(); index++ {
+		FType := p_Type.Field(index)
+		Tag := analyzeHtmlStructTag(FType.Tag.Get("html")) // analyze html struct-tag
+		if Tag.Skip { continue }                            // skip field
+		if !Tag.HasOrder {
+			Tag.Order = len(Entries) + 1
+		} // END if
+		Index := append(append(make([]int,0,len(p_Index)+1),p_Index...),index)
+		Type := FType.Type
+		Pointer := Type.Kind() == reflect.Ptr
+		if Pointer {
+			Type = Type.Elem()
+		} // END if
+		Cycle := slices.Contains(p_Path,Type) // e.g. type Dir struct{ Parent *Dir }
+		Path := append(slices.Clip(p_Path),Type)
+
+		// embedded struct: promote the fields
+		if FType.Anonymous && Type.Kind() == reflect.Struct && !Cycle && Tag.Nested != gc_NestedString && Tag.Nested != gc_NestedGroup {
+			Entries = append(Entries,t_Entry{Tag.Order,Tag.HasOrder,structColumns(p_Root,Type,Index,p_Group,p_Prefix,p_Depth+1,Path)})
+			continue
+		} // END if
+		if !FType.IsExported() { continue } // skip private field
+
+		Header := Tag.HeaderText
+		if Header == "" {
+			Header = FType.Name
+		} // END if
+
+		// nested struct: flatten or group the fields
+		if Type.Kind() == reflect.Struct && Type != v_TimeType && Tag.Nested != gc_NestedString {
+			Nested := Tag.Nested
+			if (Nested == "" && Pointer) || Cycle {
+				Nested = gc_NestedString // a pointer is one column, unless Nested says otherwise; a type containing itself always
+			} // END if
+			if Nested == "" && (Type.Implements(v_StringerType) || reflect.PointerTo(Type).Implements(v_StringerType)) {
+				Nested = gc_NestedString // the type knows how to print itself
+			} // END if
+			if Nested == "" && (Type.Implements(valuerType) || reflect.PointerTo(Type).Implements(valuerType)) {
+				Nested = gc_NestedString // sql.NullString & Co. are printed by their value
+			} // END if
+			switch {
+				case Nested == gc_NestedFlatten || (Nested != gc_NestedString && p_Group != ""): {
+					Entries = append(Entries,t_Entry{Tag.Order,Tag.HasOrder,structColumns(p_Root,Type,Index,p_Group,p_Prefix+FType.Name+".",p_Depth,Path)})
+					continue
+				} // END case
+				case Nested != gc_NestedString: { // group
+					Entries = append(Entries,t_Entry{Tag.Order,Tag.HasOrder,structColumns(p_Root,Type,Index,Header,p_Prefix+FType.Name+".",p_Depth,Path)})
+					continue
+				} // END case
+			} // END switch
+		} // END if
+
+		Entries = append(Entries,t_Entry{Tag.Order,Tag.HasOrder,[]t_StructColumn{{
+			Index:       Index,
+			Name:        p_Prefix + FType.Name,
+			Header:      Header,
+			Type:        FType.Type,
+			Group:       p_Group,
+			Tag:         Tag,
+			HeaderStyle: Tag.headerStyle(),
+			DataStyle:   Tag.dataStyle(),
+			link:        parseLink(p_Root,Tag.Link),
+			depth:       p_Depth,
+		}}})
+	} // END for
+	slices.SortStableFunc(Entries,func(a,b t_Entry) int { return cmp.Or(cmp.Compare(a.order,b.order),-cmp.Compare(boolInt(a.tagged),boolInt(b.tagged))) })
+
+	Columns := make([]t_StructColumn,0,len(Entries))
+	for _,Entry := range Entries {
+		Columns = append(Columns,Entry.columns...)
+	} // END for
+	return Columns
+} // END structColumns
+
+// Split a Link template like '/ticker/{Name}' into literals and fields
+func parseLink(p_Type reflect.Type, p_Link string) []t_LinkPart {
+	if p_Link == "" {
+		return nil
+	} // END if
+	Parts := make([]t_LinkPart,0,3)
+	for p_Link != "" {
+		Open := strings.Index(p_Link,"{")
+		Close := strings.Index(p_Link,"}")
+		if Open < 0 || Close < Open {
+			Parts = append(Parts,t_LinkPart{Literal: p_Link})
+			break
+		} // END if
+		if Open > 0 {
+			Parts = append(Parts,t_LinkPart{Literal: p_Link[:Open]})
+		} // END if
+		Name := p_Link[Open+1:Close]
+		if Name == "Key" {
+			Parts = append(Parts,t_LinkPart{Key: true})
+		} else if Field,ok := p_Type.FieldByName(Name); ok {
+			Parts = append(Parts,t_LinkPart{Field: Field.Index})
+		} else {
+			Parts = append(Parts,t_LinkPart{Literal: p_Link[Open:Close+1]}) // unknown field: keep the placeholder
+		} // END if
+		p_Link = p_Link[Close+1:]
+	} // END for
+	return Parts
+} // END parseLink
+
+// Return the value of the column in the struct p_Struct, an invalid reflect.Value if an embedded pointer is nil
+func (p_Column t_StructColumn) value(p_Struct reflect.Value) reflect.Value {
+	return fieldByIndex(p_Struct,p_Column.Index)
+} // END value
+
+// Name of the request parameter and the form field: Param of the struct-tag or the name of the column
+func (p_Column t_StructColumn) param() string {
+	if p_Column.Tag.Param != "" {
+		return p_Column.Tag.Param
+	} // END if
+	return p_Column.Name
+} // END param
+
+// Like reflect.Value.FieldByIndex, but without panic on nil pointers
+func fieldByIndex(p_Struct reflect.Value, p_Index []int) reflect.Value {
+	if Field,err := p_Struct.FieldByIndexErr(p_Index); err == nil {
+		return Field
+	} // END if
+	return reflect.Value{}
+} // END fieldByIndex
+
+// Format the value of the column in the struct p_Struct according to the html struct-tag,
+// p_Value is the value of the column, the struct is needed for the fields of the Link
+func (p_Column t_StructColumn) formatCell(p_Struct, p_Value reflect.Value, p_Key string) string {
+	Content := formatValue(p_Value,p_Column.Tag)
+	if p_Column.link == nil {
+		return Content
+	} // END if
+	var Href strings.Builder
+	for _,Part := range p_Column.link {
+		switch {
+			case Part.Key: Href.WriteString(url.PathEscape(p_Key))
+			case Part.Field != nil: {
+				if Value := derefValue(fieldByIndex(p_Struct,Part.Field)); Value.IsValid() {
+					Href.WriteString(url.PathEscape(fmt.Sprint(Value.Interface())))
+				} // END if
+			} // END case
+			default: Href.WriteString(Part.Literal)
+		} // END switch
+	} // END for
+	return Tag("a",Content,"href",html.EscapeString(Href.String()))
+} // END formatCell
+
+// Format a value according to the html struct-tag:
+// nil and zero values are replaced by Empty, time.Time is formatted by Layout, other values by Format.
+func formatValue(p_Value reflect.Value, p_Tag t_HtmlTag) string {
+	Value := sqlValue(p_Value) // sql.NullString & Co.
+	if !Value.IsValid() {
+		if p_Tag.Empty != "" {
+			return p_Tag.Empty
+		} // END if
+		return "&nbsp;"
+	} // END if
+	if p_Tag.Empty != "" && Value.IsZero() {
+		return p_Tag.Empty
+	} // END if
+	if Time,ok := Value.Interface().(time.Time); ok && p_Tag.Layout != "" {
+		return Time.Format(p_Tag.Layout)
+	} // END if
+	if p_Tag.Format != "" {
+		return fmt.Sprintf(p_Tag.Format,Value.Interface())
+	} // END if
+	return fmt.Sprint(Value.Interface())
+} // END formatValue
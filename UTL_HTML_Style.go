@@ -0,0 +1,201 @@
+package UTL_HTML
+//
+// UTL_HTML_Style
+// Version: $Id$
+//
+import (
+	"fmt"
+	"strings"
+	"reflect"
+)
+
+// *************************************************************************************
+// Conditional styling of table rows and cells: Rules decide from the values of a row
+// which classes, styles and attributes are added to <tr> and <td>. They are applied by
+// all data-row functions: TrTd, TrTdStruct, TrTdSlice, TrTdMap, TrTdSqlRows and typed tables.
+//
+//  v_Doc.StyleRows(RuleZebra("even","odd"),RuleEquals("Flying","1","flying")).
+//        StyleCells(RuleNegative("negative")).
+//        StyleRowHeader()
+
+// Additional classes, styles and attributes of a row or a cell
+type T_Style struct {
+	Class      string   // class-names, separated by blanks
+	Style      string   // CSS declarations
+	Attributes []string // attribute name/value pairs
+} // END T_Style
+
+// A data-row as seen by the rules
+type T_RowData struct {
+	Index int      // position of the data-row since the last header-row, starting with 0
+	cells []t_Cell
+} // END T_RowData
+
+// Rule for a data-row
+type T_RowRule func(p_Row T_RowData) T_Style
+
+// Rule for a data-cell: p_Column is the name of the column, p_Value its value, nil pointers are nil
+type T_CellRule func(p_Row T_RowData, p_Column string, p_Value any) T_Style
+
+// Add rules for the data-rows, they are applied in the order they were added
+func (p_HTML *T_HTML) StyleRows(p_Rules ...T_RowRule) *T_HTML {
+	p_HTML.rowRules = append(p_HTML.rowRules,p_Rules...)
+	return p_HTML
+} // END StyleRows
+
+// Add rules for the data-cells, they are applied in the order they were added
+func (p_HTML *T_HTML) StyleCells(p_Rules ...T_CellRule) *T_HTML {
+	p_HTML.cellRules = append(p_HTML.cellRules,p_Rules...)
+	return p_HTML
+} // END StyleCells
+
+// The first cell of the data-rows becomes <th scope="row">
+func (p_HTML *T_HTML) StyleRowHeader() *T_HTML {
+	p_HTML.rowHeader = true
+	return p_HTML
+} // END StyleRowHeader
+
+// Remove all rules and the row header
+func (p_HTML *T_HTML) StyleEnd() *T_HTML {
+	p_HTML.rowRules  = nil
+	p_HTML.cellRules = nil
+	p_HTML.rowHeader = false
+	return p_HTML
+} // END StyleEnd
+
+// Rule for alternating classes of even and odd rows
+func RuleZebra(p_EvenClass, p_OddClass string) T_RowRule {
+	return func(p_Row T_RowData) T_Style {
+		if p_Row.Index % 2 == 0 {
+			return T_Style{Class: p_EvenClass}
+		} // END if
+		return T_Style{Class: p_OddClass}
+	}
+} // END RuleZebra
+
+// Rule for rows where the column has the value, compared as text: RuleEquals("Flying","Yes","flying")
+func RuleEquals(p_Column string, p_Value any, p_Class string) T_RowRule {
+	Text := fmt.Sprint(p_Value)
+	return func(p_Row T_RowData) T_Style {
+		if p_Row.Text(p_Column) == Text {
+			return T_Style{Class: p_Class}
+		} // END if
+		return T_Style{}
+	}
+} // END RuleEquals
+
+// Rule for cells with negative numbers, including numbers in strings and []byte
+func RuleNegative(p_Class string) T_CellRule {
+	return func(p_Row T_RowData, p_Column string, p_Value any) T_Style {
+		if Number,ok := numericValue(derefValue(reflect.ValueOf(p_Value))); ok && Number < 0 {
+			return T_Style{Class: p_Class}
+		} // END if
+		return T_Style{}
+	}
+} // END RuleNegative
+
+// Return the value of a column, nil if the column is unknown or the value is nil
+func (p_Row T_RowData) Value(p_Column string) any {
+	for _,Cell := range p_Row.cells {
+		if Cell.name == p_Column {
+			if Value := derefValue(Cell.value); Value.IsValid() {
+				return Value.Interface()
+			} // END if
+			return nil
+		} // END if
+	} // END for
+	return nil
+} // END Value
+
+// Return the value of a column as text, "" if the column is unknown or the value is nil
+func (p_Row T_RowData) Text(p_Column string) string {
+	for _,Cell := range p_Row.cells {
+		if Cell.name == p_Column {
+			return groupText(Cell.value)
+		} // END if
+	} // END for
+	return ""
+} // END Text
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// A cell of a data-row
+type t_Cell struct {
+	name    string        // column-name for group-by, aggregates and rules
+	value   reflect.Value // the value for group-by, aggregates and rules
+	content string        // HTML content of the cell
+	class   string        // additional class-name, e.g. DataClass of the struct-tag
+	style   string        // style attribute
+	tag     t_HtmlTag     // html struct-tag with Aggregate and Format
+} // END t_Cell
+
+// Add another style: classes and declarations are appended
+func (p_Style T_Style) merge(p_Other T_Style) T_Style {
+	if p_Other.Class != "" {
+		p_Style.Class = strings.TrimSpace(p_Style.Class + " " + p_Other.Class)
+	} // END if
+	if p_Other.Style != "" {
+		if p_Style.Style != "" && !strings.HasSuffix(strings.TrimSpace(p_Style.Style),";") {
+			p_Style.Style += ";"
+		} // END if
+		p_Style.Style = strings.TrimSpace(p_Style.Style + " " + p_Other.Style)
+	} // END if
+	p_Style.Attributes = append(p_Style.Attributes,p_Other.Attributes...)
+	return p_Style
+} // END merge
+
+// Data-row from its cells, used by all data-row functions: group-by, aggregates and styling rules
+func (p_HTML *T_HTML) helper_TrTdCells(p_TrClass, p_TdClass string, p_Cells []t_Cell) *T_HTML {
+	if p_HTML.groupMode != "" {
+		p_HTML.groupRow(p_TrClass,p_TdClass,p_Cells)
+	} // END if
+	Row := T_RowData{Index: p_HTML.rowIndex, cells: p_Cells}
+	p_HTML.rowIndex++
+
+	if len(p_HTML.rowRules) == 0 {
+		p_HTML.helper_TrOpen(p_TrClass)
+	} else {
+		Style := T_Style{Class: p_TrClass}
+		for _,Rule := range p_HTML.rowRules {
+			Style = Style.merge(Rule(Row))
+		} // END for
+		Attributes := make([]string,0,4+len(Style.Attributes))
+		appendAttribute("class",Style.Class,&Attributes)
+		appendAttribute("style",Style.Style,&Attributes)
+		p_HTML.TrOpen(append(Attributes,Style.Attributes...)...)
+	} // END if
+
+	First := true
+	for column,Cell := range p_Cells {
+		p_HTML.aggregateValue(column,Cell.name,Cell.tag,Cell.style,Cell.value)
+		if p_HTML.groupMode != "" && p_HTML.groupSkip(Cell.name) { continue }
+		if len(p_HTML.cellRules) == 0 && !(First && p_HTML.rowHeader) {
+			p_HTML.helper_Td(Cell.content,p_TdClass,Cell.class,Cell.style)
+			First = false
+			continue
+		} // END if
+
+		Style := T_Style{Class: Cell.class, Style: Cell.style}
+		if len(p_HTML.cellRules) > 0 {
+			var Value any
+			if Deref := derefValue(Cell.value); Deref.IsValid() {
+				Value = Deref.Interface()
+			} // END if
+			for _,Rule := range p_HTML.cellRules {
+				Style = Style.merge(Rule(Row,Cell.name,Value))
+			} // END for
+		} // END if
+		if First && p_HTML.rowHeader {
+			p_HTML.helper_Tag("th",Cell.content,p_TdClass,Style.Class,Style.Style,append(Style.Attributes,"scope","row")...)
+		} else {
+			p_HTML.helper_Tag("td",Cell.content,p_TdClass,Style.Class,Style.Style,Style.Attributes...)
+		} // END if
+		First = false
+	} // END for
+	p_HTML.helper_TrClose()
+	if p_HTML.groupMode != "" {
+		p_HTML.groupRowDone()
+	} // END if
+	return p_HTML
+} // END helper_TrTdCells
@@ -22,7 +22,11 @@ func (p_HTML *T_HTML) helper_TrOpen(p_TrClass string) *T_HTML {
 	return p_HTML
 } // END helper_TrOpen
 
-func (p_HTML *T_HTML) helper_Tag(p_TagName, p_Content, p_TagClass, p_AdditionalClass, p_Style string) *T_HTML {
+func (p_HTML *T_HTML) helper_TrClose() *T_HTML {
+	return p_HTML.TagCloseTop().rowDone() // tr
+} // END helper_TrClose
+
+func (p_HTML *T_HTML) helper_Tag(p_TagName, p_Content, p_TagClass, p_AdditionalClass, p_Style string, p_Attributes ...string) *T_HTML {
 	TagStr := "<" + p_TagName
 	if p_TagClass != "" && p_AdditionalClass != "" {
 		TagStr += fmt.Sprintf(` class="%s %s"`,p_TagClass,p_AdditionalClass)
@@ -35,6 +39,10 @@ func (p_HTML *T_HTML) helper_Tag(p_TagName, p_Content, p_TagClass, p_AdditionalC
 	if p_Style != "" {
 		TagStr += fmt.Sprintf(` style="%s"`,p_Style)
 	} // END if
+
+	for index := 0; index+1 < len(p_Attributes); index += 2 {
+		TagStr += fmt.Sprintf(` %s="%s"`,p_Attributes[index],p_Attributes[index+1])
+	} // END for
 		
 	if p_Content != "" {
 		TagStr += fmt.Sprintf(`>%s</%s>`,p_Content,p_TagName)
@@ -53,6 +61,24 @@ func (p_HTML *T_HTML) helper_Th(p_Content, p_ThClass, p_HeaderClass, p_Style str
 	return p_HTML.helper_Tag("th",p_Content, p_ThClass, p_HeaderClass, p_Style)
 } // END helper_Th
 
+// Header cell of a sortable column, p_Key is the name of the column in the sort-link and for aggregates
+func (p_HTML *T_HTML) helper_SortTh(p_Key, p_Content, p_ThClass, p_HeaderClass, p_Style string, p_Attributes ...string) *T_HTML {
+	p_HTML.columnNames = append(p_HTML.columnNames, p_Key)
+	if p_HTML.groupHidden(p_Key) {
+		return p_HTML // group column in header mode
+	} // END if
+	if !p_HTML.sortActive {
+		return p_HTML.helper_Tag("th",p_Content, p_ThClass, p_HeaderClass, p_Style, p_Attributes...)
+	} // END if
+	if p_Key != p_HTML.sortColumn {
+		return p_HTML.helper_Tag("th",Tag("a",p_Content,"href",p_HTML.linkURL(GC_ParamSort,p_Key,GC_ParamDir,"asc")), p_ThClass, p_HeaderClass, p_Style, p_Attributes...)
+	} else if p_HTML.sortDesc {
+		return p_HTML.helper_Tag("th",Tag("a",p_Content+"&nbsp;▼","href",p_HTML.linkURL(GC_ParamSort,p_Key,GC_ParamDir,"asc")), p_ThClass, p_HeaderClass, p_Style,append(p_Attributes,"aria-sort","descending")...)
+	} else {
+		return p_HTML.helper_Tag("th",Tag("a",p_Content+"&nbsp;▲","href",p_HTML.linkURL(GC_ParamSort,p_Key,GC_ParamDir,"desc")), p_ThClass, p_HeaderClass, p_Style,append(p_Attributes,"aria-sort","ascending")...)
+	} // END if
+} // END helper_SortTh
+
 func (p_HTML *T_HTML) helper_Td(p_Content, p_TdClass, p_DataClass, p_Style string) *T_HTML {
 	return p_HTML.helper_Tag("td",p_Content, p_TdClass, p_DataClass, p_Style)
 } // END helper_Td
@@ -83,14 +109,17 @@ func (p_HTML *T_HTML) TdOpen(p_Attributes ...string) *T_HTML { 	return p_HTML.Ta
 //
 func (p_HTML *T_HTML) TrTh(p_TrClass, p_ThClass string, p_DataItems ...any) *T_HTML {
 	p_HTML.helper_TrOpen(p_TrClass)
+	p_HTML.headerStart()
 	for _,DataItem := range(p_DataItems) {
 		if reflect.TypeOf(DataItem).Kind() != reflect.Ptr {
-	    p_HTML.helper_Th(fmt.Sprint(DataItem),p_ThClass,"","")
+	    p_HTML.helper_SortTh(fmt.Sprint(DataItem),fmt.Sprint(DataItem),p_ThClass,"","")
 		} else {
 			if reflect.ValueOf(DataItem).IsNil() {
+				p_HTML.columnNames = append(p_HTML.columnNames,"") // keep the positions of the columns
 				p_HTML.helper_Th("&nbsp;",p_ThClass,"","")
 			} else {
-	      p_HTML.helper_Th(fmt.Sprint(reflect.ValueOf(DataItem).Elem().Interface()),p_ThClass,"","")
+				Header := fmt.Sprint(reflect.ValueOf(DataItem).Elem().Interface())
+	      p_HTML.helper_SortTh(Header,Header,p_ThClass,"","")
 			} // END if
 		} // END if
 	} // END for
@@ -103,15 +132,16 @@ func (p_HTML *T_HTML) TrTh(p_TrClass, p_ThClass string, p_DataItems ...any) *T_H
 //  - p_DataItems - Any data-type that is printable with fmt.Sprint; pointer-types will be dereferenced
 //
 func (p_HTML *T_HTML) TrTd(p_TrClass, p_TdClass string, p_DataItems ...any) *T_HTML {
-	p_HTML.helper_TrOpen(p_TrClass)
-	for _,DataItem := range(p_DataItems) {
+	Cells := make([]t_Cell,len(p_DataItems))
+	for column,DataItem := range(p_DataItems) {
+		Cells[column] = t_Cell{name: p_HTML.columnName(column), value: reflect.ValueOf(DataItem)}
 		if reflect.TypeOf(DataItem).Kind() != reflect.Ptr {
- 		  p_HTML.helper_Td(fmt.Sprint(DataItem),p_TdClass,"","")
+ 		  Cells[column].content = fmt.Sprint(DataItem)
 		} else {
- 		  p_HTML.helper_Td(fmt.Sprint(reflect.ValueOf(DataItem).Elem().Interface()),p_TdClass,"","")
+ 		  Cells[column].content = fmt.Sprint(reflect.ValueOf(DataItem).Elem().Interface())
 		} // END if
 	} // END for
-	return p_HTML.TagCloseTop() // tr
+	return p_HTML.helper_TrTdCells(p_TrClass,p_TdClass,Cells)
 } // END TrTd
 
 // Create table header-row from the field-names of a struct{}
@@ -122,29 +152,68 @@ func (p_HTML *T_HTML) TrTd(p_TrClass, p_TdClass string, p_DataItems ...any) *T_H
 //
 // - Non exported fields of the struct{} are skipped
 // - html struct-tags are interpreted
+// - Fields of embedded structs are promoted, nested structs become grouped columns with a two-row header
 func (p_HTML *T_HTML) TrThStruct(p_TrClass, p_ThClass, p_KeyColHeader string, p_DataItem any) *T_HTML {
 	SType := reflect.TypeOf(p_DataItem)
 	if SType.Kind() != reflect.Struct { panic(fmt.Sprintf("Unknown datatype used in TrThStruct: %s",SType)) }
+	Meta := structMeta(SType)
+	if Meta.Grouped {
+		return p_HTML.helper_TrThGrouped(p_TrClass,p_ThClass,p_KeyColHeader,Meta)
+	} // END if
 	
 	p_HTML.helper_TrOpen(p_TrClass)
+	p_HTML.headerStart()
 	if p_KeyColHeader != "" {
-		p_HTML.helper_Th(p_KeyColHeader,p_ThClass,"","") // Add key-column header for map[]struct{}
+		p_HTML.helper_SortTh(p_KeyColHeader,p_KeyColHeader,p_ThClass,"","") // Add key-column header for map[]struct{}
 	} // END if
-	for index := 0; index < SType.NumField(); index++ {
-		FType := SType.Field(index)
-		if FType.Name[0] < 'A' || FType.Name[0] >'Z' { continue } 												// skip private field
- 		ColHeader,HeaderClass,_,_,Skip := analyzeHtmlStructTag(FType.Tag.Get("html")) // analyze html struct-tag
-	  if Skip { continue } // skip field
-	  if ColHeader != "" {
-	    p_HTML.helper_Th(ColHeader,p_ThClass,HeaderClass,"")
-		} else {
-	    p_HTML.helper_Th(FType.Name,p_ThClass,HeaderClass,"")
-		} // END if
-	} // END for index
+	for _,Column := range Meta.Columns {
+	  p_HTML.helper_SortTh(Column.Name,Column.Header,p_ThClass,Column.Tag.HeaderClass,Column.HeaderStyle)
+	} // END for
 	
 	return p_HTML.TagCloseTop() // tr
 } // END TrThStruct
 
+// Two header-rows for structs with grouped columns: the first row contains the group labels
+// with colspan and the columns without group with rowspan, the second row the grouped columns.
+func (p_HTML *T_HTML) helper_TrThGrouped(p_TrClass, p_ThClass, p_KeyColHeader string, p_Meta *t_StructMeta) *T_HTML {
+	p_HTML.helper_TrOpen(p_TrClass)
+	p_HTML.headerStart()
+	if p_KeyColHeader != "" {
+		p_HTML.helper_SortTh(p_KeyColHeader,p_KeyColHeader,p_ThClass,"","","rowspan","2")
+	} // END if
+	for index := 0; index < len(p_Meta.Columns); index++ {
+		Column := p_Meta.Columns[index]
+		if Column.Group == "" {
+			p_HTML.helper_SortTh(Column.Name,Column.Header,p_ThClass,Column.Tag.HeaderClass,Column.HeaderStyle,"rowspan","2")
+			continue
+		} // END if
+		Span := 1
+		for index+Span < len(p_Meta.Columns) && p_Meta.Columns[index+Span].Group == Column.Group {
+			Span++
+		} // END for
+		p_HTML.helper_Tag("th",Column.Group,p_ThClass,"","","colspan",fmt.Sprint(Span),"scope","colgroup")
+		index += Span-1
+	} // END for
+	p_HTML.TagCloseTop() // tr
+
+	p_HTML.helper_TrOpen(p_TrClass)
+	for _,Column := range p_Meta.Columns {
+		if Column.Group != "" {
+			p_HTML.helper_SortTh(Column.Name,Column.Header,p_ThClass,Column.Tag.HeaderClass,Column.HeaderStyle)
+		} // END if
+	} // END for
+
+	// the column names in the order of the data columns
+	p_HTML.columnNames = p_HTML.columnNames[:0]
+	if p_KeyColHeader != "" {
+		p_HTML.columnNames = append(p_HTML.columnNames,p_KeyColHeader)
+	} // END if
+	for _,Column := range p_Meta.Columns {
+		p_HTML.columnNames = append(p_HTML.columnNames,Column.Name)
+	} // END for
+	return p_HTML.TagCloseTop() // tr
+} // END helper_TrThGrouped
+
 // Create table header-row from the field-names of a struct{}
 //  - p_TrClass - CSS classname for <tr>
 //  - p_ThClass - CSS classname for <th>
@@ -153,40 +222,36 @@ func (p_HTML *T_HTML) TrThStruct(p_TrClass, p_ThClass, p_KeyColHeader string, p_
 //
 // Non exported fields of the structure are skipped
 // - html struct-tags are interpreted
-// - Pointer fields are dereferenced and nil is replaced with "&nbsp;"
+// - Pointer fields are dereferenced and nil is replaced with "&nbsp;" or the Empty value of the struct-tag
 //
 func (p_HTML *T_HTML) TrTdStruct(p_TrClass, p_TdClass, p_KeyColValue string, p_DataItem any) *T_HTML {
 	SType := reflect.TypeOf(p_DataItem)
 	if SType.Kind() != reflect.Struct { panic(fmt.Sprintf("Unknown datatype used in TrThStruct: %s",SType)) }
-	
-	p_HTML.helper_TrOpen(p_TrClass)
-	if p_KeyColValue != "" {
-		p_HTML.helper_Td(p_KeyColValue,p_TdClass,"","") // Add key-column value for map[]struct{}
-	} // END if
-	
-	SValues := reflect.ValueOf(p_DataItem)
-	for index := 0; index < SType.NumField(); index++ {
-		FType := SType.Field(index)
- 	  if FType.Name[0] < 'A' || FType.Name[0] >'Z' { continue } 							// skip private field
-		_,_,DataClass,Style,Skip := analyzeHtmlStructTag(FType.Tag.Get("html")) // analyze html struct-tag
-		//fmt.Printf("Fieldname='%s'; DataClass='%s',Style='%s'; Skip='%t'\n",FType.Name,DataClass,Style,Skip)
-		//fmt.Printf("htmltag=»%s«\n",FType.Tag.Get("html"))
-		if Skip { continue } // skip field
-		Field := SValues.Field(index)
-		if Field.Kind() == reflect.Ptr {
-			if Field.IsNil() {
-			  p_HTML.helper_Td("&nbsp;",p_TdClass,DataClass,Style)
-			} else {
-			  p_HTML.helper_Td(fmt.Sprint(Field.Elem().Interface()),p_TdClass,DataClass,Style)
-			} // END if
-    } else {
-		  p_HTML.helper_Td(fmt.Sprint(Field.Interface()),p_TdClass,DataClass,Style)
-    } // END if
-	} // END for index
-	
-	return p_HTML.TagCloseTop() // tr
+	return p_HTML.helper_TrTdStruct(p_TrClass,p_TdClass,p_KeyColValue,reflect.ValueOf(p_DataItem),structMeta(SType))
 } // END TrTdStruct
 
+// Data-row of a struct{} with the metadata of its type, used by TrTdStruct, TrTdSlice and TrTdMap
+func (p_HTML *T_HTML) helper_TrTdStruct(p_TrClass, p_TdClass, p_KeyColValue string, p_Struct reflect.Value, p_Meta *t_StructMeta) *T_HTML {
+	Cells := p_HTML.cellBuffer[:0]
+	if p_KeyColValue != "" { // Add key-column value for map[]struct{}
+		Cells = append(Cells,t_Cell{name: p_HTML.columnName(0), value: reflect.ValueOf(p_KeyColValue), content: p_KeyColValue})
+	} // END if
+	for _,Column := range p_Meta.Columns {
+		Value := Column.value(p_Struct)
+		Cells = append(Cells,t_Cell{
+			name:    Column.Name,
+			value:   Value,
+			content: Column.formatCell(p_Struct,Value,p_KeyColValue),
+			class:   Column.Tag.DataClass,
+			style:   Column.DataStyle,
+			tag:     Column.Tag,
+		})
+	} // END for
+	p_HTML.cellBuffer = Cells // reused by the next row
+	return p_HTML.helper_TrTdCells(p_TrClass,p_TdClass,Cells)
+} // END helper_TrTdStruct
+
+
 // Convert a map into HTML-table rows.
 // The following map types can be processed:
 //  - map[KeyType]any → two columns: Key, Value
@@ -207,19 +272,28 @@ func (p_HTML *T_HTML) TrTdMap(p_TrClass, p_TdClass string, p_CompareFunc t_Compa
 	if p_CompareFunc != nil {
 		slices.SortFunc(Keys,p_CompareFunc)
 	} // END if
+	if Column := p_HTML.sortIndex(); Column == 0 {
+		slices.SortStableFunc(Keys,p_HTML.sortCompare)
+	} else if Column > 0 {
+		slices.SortStableFunc(Keys,func(a,b reflect.Value) int {
+			return p_HTML.sortCompare(columnValue(DataItems.MapIndex(a),Column-1),columnValue(DataItems.MapIndex(b),Column-1))
+		})
+	} // END if
+	Keys = pageSlice(p_HTML,Keys)
 	for _,key := range Keys {
+		if p_HTML.Cancelled() { break } // client is gone
 		if DataItems.MapIndex(key).Kind() == reflect.Ptr {
 			if DataItems.MapIndex(key).Elem().Kind() == reflect.Struct {
 			  // map[KeyType]*struct{}
-        p_HTML.TrTdStruct(p_TrClass, p_TdClass,fmt.Sprint(key.Interface()), DataItems.MapIndex(key).Elem().Interface()) // print struct{} fields as columns
+        p_HTML.helper_TrTdStruct(p_TrClass, p_TdClass,fmt.Sprint(key.Interface()), DataItems.MapIndex(key).Elem(), structMeta(DataItems.MapIndex(key).Elem().Type())) // print struct{} fields as columns
 			} else {
  				// Assumption: map[KeyType]*SimpleType
-	      p_HTML.helper_TrOpen(p_TrClass).helper_Td(fmt.Sprint(key),p_TdClass,"","").helper_Td(fmt.Sprint(DataItems.MapIndex(key).Elem()),p_TdClass,"","").TagCloseTop() 
+	      p_HTML.helper_TrTdKeyValue(p_TrClass,p_TdClass,key,DataItems.MapIndex(key).Elem())
 			} // END if
 		} else {
 			switch DataItems.MapIndex(key).Kind() {
 				case reflect.Struct: { // map[KeyType]struct{}
-          p_HTML.TrTdStruct(p_TrClass, p_TdClass,fmt.Sprint(key.Interface()), DataItems.MapIndex(key).Interface()) // print struct{} into columns
+          p_HTML.helper_TrTdStruct(p_TrClass, p_TdClass,fmt.Sprint(key.Interface()), DataItems.MapIndex(key), structMeta(DataItems.MapIndex(key).Type())) // print struct{} into columns
 				} // END case
 				case reflect.Slice: { // map[KeyType][]any
 					RowLen := DataItems.MapIndex(key).Len()
@@ -235,15 +309,24 @@ func (p_HTML *T_HTML) TrTdMap(p_TrClass, p_TdClass string, p_CompareFunc t_Compa
 					p_HTML.TrTd(p_TrClass,p_TdClass,Arguments...)
 				} // END case
 				default: { // Assumption: map[KeyType]SimpleType
-					p_HTML.helper_TrOpen(p_TrClass).helper_Td(fmt.Sprint(key),p_TdClass,"","").helper_Td(fmt.Sprint(DataItems.MapIndex(key)),p_TdClass,"","").TagCloseTop() 
+					p_HTML.helper_TrTdKeyValue(p_TrClass,p_TdClass,key,DataItems.MapIndex(key))
 				} // END default:
 			} // END switch
 		} // END if
 	} // END for
+	p_HTML.groupEnd(p_TrClass,p_TdClass)
 
 	return p_HTML
 } // END TrTdMap
 
+// Data-row of a map[KeyType]SimpleType with the columns key and value
+func (p_HTML *T_HTML) helper_TrTdKeyValue(p_TrClass, p_TdClass string, p_Key, p_Value reflect.Value) *T_HTML {
+	return p_HTML.helper_TrTdCells(p_TrClass,p_TdClass,[]t_Cell{
+		{name: p_HTML.columnName(0), value: p_Key, content: fmt.Sprint(p_Key)},
+		{name: p_HTML.columnName(1), value: p_Value, content: fmt.Sprint(p_Value)},
+	})
+} // END helper_TrTdKeyValue
+
 	
 // Convert a slice into HTML-table row(s).
 // The following slice types can be processed:
@@ -273,51 +356,63 @@ func (p_HTML *T_HTML) TrTdSlice(p_TrClass, p_TdClass string, p_DataRows any) *T_
 	} // END if
   switch EleType {
 		case reflect.Struct: { // []struct or []*struct
-	  fmt.Println("TrTdSlice: []struct or []*struct")
-			for row := 0; row < NumRows; row++ {
-				if DataRows.Index(row).Kind() == reflect.Ptr {
-					if !DataRows.Index(row).IsNil() {
-			      p_HTML.TrTdStruct(p_TrClass,p_TdClass,"",DataRows.Index(row).Elem().Interface())
+			for _,Row := range p_HTML.sortRows(DataRows) {
+				if p_HTML.Cancelled() { break } // client is gone
+				if Row.Kind() == reflect.Ptr {
+					if !Row.IsNil() {
+			      p_HTML.helper_TrTdStruct(p_TrClass,p_TdClass,"",Row.Elem(),structMeta(Row.Elem().Type()))
 					} // END if
 				} else {
-			    p_HTML.TrTdStruct(p_TrClass,p_TdClass,"",DataRows.Index(row).Interface())
+			    p_HTML.helper_TrTdStruct(p_TrClass,p_TdClass,"",Row,structMeta(Row.Type()))
 				} // END if
 			} // END for
 		} // END case
-		case reflect.Slice: { // [][]any or []*[]any ¡recursion!
-      for row := 0; row < NumRows; row++ {
-				if DataRows.Index(row).Kind() == reflect.Ptr {
-		      p_HTML.TrTdSlice(p_TrClass,p_TdClass,DataRows.Index(row).Elem().Interface())
+		case reflect.Slice: { // [][]any or []*[]any
+      for _,Row := range p_HTML.sortRows(DataRows) {
+				if p_HTML.Cancelled() { break } // client is gone
+				if Row.Kind() == reflect.Ptr {
+					if !Row.IsNil() {
+		        p_HTML.helper_TrTdValues(p_TrClass,p_TdClass,Row.Elem(),nil)
+					} // END if
 				} else {
-			    p_HTML.TrTdSlice(p_TrClass,p_TdClass,DataRows.Index(row).Interface())
+			    p_HTML.helper_TrTdValues(p_TrClass,p_TdClass,Row,nil)
 				} // END if
 			} // END for
 		} // END case
 		default: { // assuming []any
-			p_HTML.helper_TrOpen(p_TrClass)
-			for column := 0; column < NumRows; column++  {
-				if DataRows.Index(column).Kind() == reflect.Ptr {
-					if DataRows.Index(column).IsNil() {
-					  p_HTML.helper_Td("&nbsp;",p_TdClass,"","")
-					} else {
-					  p_HTML.helper_Td(fmt.Sprint(DataRows.Index(column).Interface()),p_TdClass,"","")
-					} // END if
-				} else {
-					p_HTML.helper_Td(fmt.Sprint(DataRows.Index(column).Interface()),p_TdClass,"","")
-				} // END if
-			} // END for
+			p_HTML.helper_TrTdValues(p_TrClass,p_TdClass,DataRows,nil)
 	  } // END default
 	} // END switch		
+	p_HTML.groupEnd(p_TrClass,p_TdClass)
 
 	return p_HTML
 } // END TrTdSlice
 
+// Data-row with one column for each element of a slice, used by TrTdSlice and TrTdSqlRows.
+// p_Columns are the column types of a result set, nil for slices.
+func (p_HTML *T_HTML) helper_TrTdValues(p_TrClass, p_TdClass string, p_Values reflect.Value, p_Columns []t_SqlColumn) *T_HTML {
+	Cells := p_HTML.cellBuffer[:0]
+	for column := 0; column < p_Values.Len(); column++  {
+		Cell := t_Cell{name: p_HTML.columnName(column), value: sqlValue(p_Values.Index(column))}
+		if column < len(p_Columns) {
+			Cell.content = p_Columns[column].text(Cell.value)
+			Cell.style   = p_Columns[column].style
+		} else {
+			Cell.content = formatValue(Cell.value,t_HtmlTag{})
+		} // END if
+		Cells = append(Cells,Cell)
+	} // END for
+	p_HTML.cellBuffer = Cells // reused by the next row
+	return p_HTML.helper_TrTdCells(p_TrClass,p_TdClass,Cells)
+} // END helper_TrTdValues
+
 //
 // Convert the resultset of an SQL-Query into HTML-table header-row(s).
 //
 func (p_HTML *T_HTML) TrThSqlRows(p_TrClass, p_ThClass string, p_DataRows *sql.Rows) *T_HTML {
 	if ColumnNames, err := p_DataRows.Columns(); err != nil {
-		panic(err)
+		p_HTML.setError(err) // e.g. the rows are closed
+		return p_HTML
 	} else {
 		ColumnHeaders := make([]any,len(ColumnNames),len(ColumnNames))
 		for i := range ColumnNames {
@@ -329,22 +424,56 @@ func (p_HTML *T_HTML) TrThSqlRows(p_TrClass, p_ThClass string, p_DataRows *sql.R
 
 //
 // Convert the resultset of an SQL-Query into HTML-table row(s).
+// []byte is printed as text, NULL as empty cell, sql.Null* by their value. The column types
+// of the result set decide about formatting: numbers are right-aligned, DECIMAL columns keep
+// their scale and time.Time is printed as date, time or both.
+// Errors of Scan() and Rows.Err() stop the rows and are returned by Err().
 //
 func (p_HTML *T_HTML) TrTdSqlRows(p_TrClass, p_TdClass string, p_DataRows *sql.Rows) *T_HTML {
 	ColumnNames, err := p_DataRows.Columns()
-	if err != nil { panic(err ) }
+	if err != nil {
+		p_HTML.setError(err)
+		return p_HTML
+	} // END if
+	Columns := sqlColumns(p_DataRows,len(ColumnNames))
 	
 	NumberOfColumns := len(ColumnNames)
+	if len(p_HTML.columnNames) == 0 {
+		p_HTML.columnNames = append(p_HTML.columnNames,ColumnNames...) // no header-row: the names for the aggregates
+	} // END if
+	KeyIndex := p_HTML.keysetIndex(ColumnNames) // keyset pagination: remember the key of the last row
+	RowCount := 0
+	p_HTML.sqlMore = false
 	for p_DataRows.Next() {
+		if p_HTML.Cancelled() { break } // client is gone
+		if KeyIndex >= 0 && RowCount == p_HTML.pageSize {
+			p_HTML.keyMore = true // KeysetQuery fetches one row more than a page
+			break
+		} // END if
+		if p_HTML.sqlMax > 0 && RowCount == p_HTML.sqlMax {
+			p_HTML.sqlMore = true // QueryTable: more rows than allowed
+			break
+		} // END if
 		RowPointers := make([]any,NumberOfColumns)
 		RowValues   := make([]any,NumberOfColumns)
 		for index := range RowValues {
 			RowPointers[index] = &RowValues[index]
 		} // END for index
 		
-		if err := p_DataRows.Scan(RowPointers...); err != nil { panic(err) }
-		p_HTML.TrTdSlice(p_TrClass,p_TdClass,RowValues)
-		
+		if err := p_DataRows.Scan(RowPointers...); err != nil {
+			p_HTML.setError(err)
+			break
+		} // END if
+		p_HTML.helper_TrTdValues(p_TrClass,p_TdClass,reflect.ValueOf(RowValues),Columns)
+		RowCount++
+		if KeyIndex >= 0 {
+			p_HTML.keysetNext(sqlValue(reflect.ValueOf(RowValues[KeyIndex])))
+		} // END if
 	} // END for
+	if err := p_DataRows.Err(); err != nil {
+		p_HTML.setError(err)
+	} // END if
+	p_HTML.sqlRows = RowCount
+	p_HTML.groupEnd(p_TrClass,p_TdClass)
 	return p_HTML
 } // END TrTdSqlRows
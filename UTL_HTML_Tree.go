@@ -0,0 +1,279 @@
+package UTL_HTML
+//
+// UTL_HTML_Tree
+// Version: $Id$
+//
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"reflect"
+	"database/sql"
+)
+
+// *************************************************************************************
+// Tree views: Tree renders hierarchical data as nested lists of <details>/<summary>
+// elements, which the browser opens and closes without JavaScript. The nodes come from
+// nested maps, slices and structs - as for List - or from the result set of an SQL query
+// with one row per node and the key of the parent node (adjacency list). Each node shows
+// the number of its children; the first levels can be expanded.
+//
+//  map[K]V / []T / struct → the elements and fields are the nodes, slices, maps and structs among them have children,
+//                           empty ones are leaves
+//  *sql.Rows              → the columns KeyField, ParentField and LabelField, default: the first three columns;
+//                           rows whose parent is NULL, "" or not in the result set are top-level nodes
+//
+//  <ul class="TreeClass">
+//    <li><details class="NodeClass" open><summary>Label <span class="CountClass">2</span></summary><ul>…</ul></details></li>
+//    <li class="LeafClass">Label</li>
+//  </ul>
+
+// Definition of a tree view
+type T_Tree struct {
+	TreeClass   string                         // CSS classname of the outermost <ul>
+	NodeClass   string                         // CSS classname of the <details> of nodes with children
+	LeafClass   string                         // CSS classname of the <li> of nodes without children
+	CountClass  string                         // CSS classname of the <span> with the number of children
+	NoCount     bool                           // don't show the number of children
+	Open        int                            // number of expanded levels: 0 all collapsed, 1 the top level, … -1 all
+	Compare     t_CompareFunc                  // sort order, maps by key and result sets by label; default: maps by CmpAsc, the others unsorted
+	MaxDepth    int                            // nested data: maximum depth of the tree, deeper data is printed with fmt.Sprint; 0: GC_ListMaxDepth
+	KeyField    string                         // *sql.Rows: column with the key of the node
+	ParentField string                         // *sql.Rows: column with the key of the parent node
+	LabelField  string                         // *sql.Rows: column with the label of the node
+	Label       func(p_Node T_TreeNode) string // HTML label of a node, "": the default label
+} // END T_Tree
+
+// A node of a tree, passed to T_Tree.Label
+type T_TreeNode struct {
+	Key      string // the map key or the header of the struct field, "" for elements of slices; *sql.Rows: the value of KeyField
+	Index    int    // position among its siblings
+	Depth    int    // level of the node, 0 for the top level
+	Children int    // number of children
+	Leaf     bool   // the node has no children
+	Value    any    // the value of the node; *sql.Rows: a map[string]any with the columns of the row
+} // END T_TreeNode
+
+// Append a tree view of nested data or of the result set of an SQL query
+func (p_HTML *T_HTML) Tree(p_Tree T_Tree, p_DataItems any) *T_HTML {
+	if p_Tree.MaxDepth <= 0 {
+		p_Tree.MaxDepth = GC_ListMaxDepth
+	} // END if
+	var Nodes []*t_TreeNode
+	if Rows,ok := p_DataItems.(*sql.Rows); ok {
+		var err error
+		if Nodes,err = treeSqlRows(Rows,&p_Tree); err != nil {
+			p_HTML.setError(err) // the nodes read so far are shown
+		} // END if
+	} else {
+		DataItems := derefValue(reflect.ValueOf(p_DataItems))
+		if !listNested(DataItems) { panic(fmt.Sprintf("Unknown datatype used in Tree: %T",p_DataItems)) }
+		Nodes = treeChildren(&p_Tree,DataItems,0)
+	} // END if
+	p_HTML.TagOpen("ul",listClass([]string{p_Tree.TreeClass},0)...)
+	p_HTML.treeNodes(&p_Tree,Nodes)
+	return p_HTML.TagCloseTop()
+} // END Tree
+
+// Return a tree view as a string, errors of *sql.Rows panic
+func Tree(p_Tree T_Tree, p_DataItems any) string {
+	HTML := New(GC_DocTypeNONE,0x00).Tree(p_Tree,p_DataItems)
+	if err := HTML.Err(); err != nil { panic(err) }
+	return HTML.String()
+} // END Tree
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+// A node with its children and the default label
+type t_TreeNode struct {
+	T_TreeNode
+	label    string
+	children []*t_TreeNode
+} // END t_TreeNode
+
+// Append the nodes as <li>s, nodes with children as <details>
+func (p_HTML *T_HTML) treeNodes(p_Tree *T_Tree, p_Nodes []*t_TreeNode) {
+	for _,Node := range p_Nodes {
+		if p_HTML.Cancelled() { break } // client is gone
+		Label := ""
+		if p_Tree.Label != nil {
+			Label = p_Tree.Label(Node.T_TreeNode)
+		} // END if
+		if Label == "" {
+			Label = Node.label
+		} // END if
+		if Node.Leaf {
+			p_HTML.tagClosed("li",Label,listClass([]string{p_Tree.LeafClass},0)...)
+			continue
+		} // END if
+		if !p_Tree.NoCount {
+			Label += " " + tagClosed("span",strconv.Itoa(Node.Children),listClass([]string{p_Tree.CountClass},0)...)
+		} // END if
+		Arguments := listClass([]string{p_Tree.NodeClass},0)
+		if p_Tree.Open < 0 || Node.Depth < p_Tree.Open {
+			Arguments = append(Arguments,"open","open")
+		} // END if
+		p_HTML.LiOpen().TagOpen("details",Arguments...).tagClosed("summary",Label)
+		if len(Node.children) > 0 {
+			p_HTML.UlOpen()
+			p_HTML.treeNodes(p_Tree,Node.children)
+			p_HTML.TagCloseTop()
+		} // END if
+		p_HTML.TagCloseTop().TagCloseTop() // details, li
+	} // END for
+} // END treeNodes
+
+// The nodes of the elements of a slice, a map or the fields of a struct
+func treeChildren(p_Tree *T_Tree, p_Value reflect.Value, p_Depth int) []*t_TreeNode {
+	Nodes := []*t_TreeNode{}
+	switch p_Value.Kind() {
+		case reflect.Slice, reflect.Array: {
+			for index := 0; index < p_Value.Len(); index++ {
+				Nodes = append(Nodes,treeNode(p_Tree,T_TreeNode{Index: index, Depth: p_Depth},p_Value.Index(index),listFormat))
+			} // END for
+		} // END case
+		case reflect.Map: {
+			Keys := p_Value.MapKeys()
+			Compare := p_Tree.Compare
+			if Compare == nil {
+				Compare = CmpAsc // maps have no order of their own
+			} // END if
+			slices.SortStableFunc(Keys,Compare)
+			for index,Key := range Keys {
+				Nodes = append(Nodes,treeNode(p_Tree,T_TreeNode{Key: groupText(Key), Index: index, Depth: p_Depth},p_Value.MapIndex(Key),listFormat))
+			} // END for
+		} // END case
+		case reflect.Struct: {
+			Nodes = treeStruct(p_Tree,p_Value,structMeta(p_Value.Type()).Columns,false,p_Depth)
+		} // END case
+	} // END switch
+	return Nodes
+} // END treeChildren
+
+// The nodes of the fields of a struct; the columns of a group become the children of a node with the group header
+func treeStruct(p_Tree *T_Tree, p_Struct reflect.Value, p_Columns []t_StructColumn, p_Group bool, p_Depth int) []*t_TreeNode {
+	Nodes := []*t_TreeNode{}
+	for index := 0; index < len(p_Columns); index++ {
+		Column := p_Columns[index]
+		if Column.Group != "" && !p_Group && p_Depth+1 < p_Tree.MaxDepth {
+			End := index + 1
+			for End < len(p_Columns) && p_Columns[End].Group == Column.Group {
+				End++
+			} // END for
+			Node := &t_TreeNode{T_TreeNode: T_TreeNode{Key: Column.Group, Index: len(Nodes), Depth: p_Depth}, label: Column.Group}
+			Node.children = treeStruct(p_Tree,p_Struct,p_Columns[index:End],true,p_Depth+1)
+			Node.Children = len(Node.children)
+			Nodes = append(Nodes,Node)
+			index = End - 1
+			continue
+		} // END if
+		Nodes = append(Nodes,treeNode(p_Tree,T_TreeNode{Key: Column.Header, Index: len(Nodes), Depth: p_Depth},Column.value(p_Struct),func(p_Value reflect.Value) string {
+			return Column.formatCell(p_Struct,p_Value,"")
+		}))
+	} // END for
+	return Nodes
+} // END treeStruct
+
+// A node of nested data: slices, maps and structs have children, other values are leaves labelled "key: value".
+// Empty slices, maps and structs are leaves labelled with the key only.
+func treeNode(p_Tree *T_Tree, p_Node T_TreeNode, p_Value reflect.Value, p_Format func(reflect.Value) string) *t_TreeNode {
+	Node := &t_TreeNode{T_TreeNode: p_Node}
+	if p_Value.IsValid() && p_Value.CanInterface() {
+		Node.Value = p_Value.Interface()
+	} // END if
+	if Value := derefValue(p_Value); listNested(Value) && p_Node.Depth+1 < p_Tree.MaxDepth {
+		Node.children = treeChildren(p_Tree,Value,p_Node.Depth+1)
+		Node.Children = len(Node.children)
+		Node.Leaf = Node.Children == 0
+		Node.label = Node.Key
+		if Node.Key == "" {
+			Node.label = strconv.Itoa(Node.Index) // element of a slice
+		} // END if
+		return Node
+	} // END if
+	Node.Leaf = true
+	Node.label = p_Format(p_Value)
+	if Node.Key != "" {
+		Node.label = Node.Key + ": " + Node.label
+	} // END if
+	return Node
+} // END treeNode
+
+// Read the nodes of a result set and link them to their parents, return the top-level nodes
+func treeSqlRows(p_DataRows *sql.Rows, p_Tree *T_Tree) ([]*t_TreeNode, error) {
+	ColumnNames, err := p_DataRows.Columns()
+	if err != nil {
+		return nil, err
+	} // END if
+	KeyIndex, ParentIndex, LabelIndex := 0, 1, min(2,len(ColumnNames)-1)
+	if p_Tree.KeyField != "" {
+		KeyIndex = slices.Index(ColumnNames,p_Tree.KeyField)
+	} // END if
+	if p_Tree.ParentField != "" {
+		ParentIndex = slices.Index(ColumnNames,p_Tree.ParentField)
+	} // END if
+	if p_Tree.LabelField != "" {
+		LabelIndex = slices.Index(ColumnNames,p_Tree.LabelField)
+	} // END if
+	if len(ColumnNames) < 2 || KeyIndex < 0 || ParentIndex < 0 || LabelIndex < 0 {
+		panic(fmt.Sprintf("Tree: unknown column in %v",ColumnNames))
+	} // END if
+
+	RowPointers := make([]any,len(ColumnNames))
+	RowValues   := make([]any,len(ColumnNames))
+	for index := range RowValues {
+		RowPointers[index] = &RowValues[index]
+	} // END for index
+	Nodes   := []*t_TreeNode{}
+	Parents := []string{}
+	Keys    := make(map[string]*t_TreeNode)
+	for p_DataRows.Next() {
+		if err = p_DataRows.Scan(RowPointers...); err != nil {
+			break
+		} // END if
+		Row := make(map[string]any,len(ColumnNames))
+		for index,Name := range ColumnNames {
+			if Value := sqlValue(reflect.ValueOf(RowValues[index])); Value.IsValid() {
+				Row[Name] = Value.Interface()
+			} else {
+				Row[Name] = nil
+			} // END if
+		} // END for
+		Key := groupText(sqlValue(reflect.ValueOf(RowValues[KeyIndex])))
+		Node := &t_TreeNode{T_TreeNode: T_TreeNode{Key: Key, Value: Row}, label: groupText(sqlValue(reflect.ValueOf(RowValues[LabelIndex])))}
+		if _,ok := Keys[Key]; !ok {
+			Keys[Key] = Node // the first row of a key gets the children
+		} // END if
+		Nodes = append(Nodes,Node)
+		Parents = append(Parents,groupText(sqlValue(reflect.ValueOf(RowValues[ParentIndex]))))
+	} // END for
+	if err == nil {
+		err = p_DataRows.Err()
+	} // END if
+
+	// nodes in a cycle without top-level node are not reachable and not shown
+	Roots := []*t_TreeNode{}
+	for index,Node := range Nodes {
+		if Parent,ok := Keys[Parents[index]]; ok && Parent != Node {
+			Parent.children = append(Parent.children,Node)
+		} else {
+			Roots = append(Roots,Node)
+		} // END if
+	} // END for
+	treeLink(p_Tree,Roots,0)
+	return Roots, err
+} // END treeSqlRows
+
+// Set position, depth and number of children of the nodes of a result set, sorted by p_Tree.Compare
+func treeLink(p_Tree *T_Tree, p_Nodes []*t_TreeNode, p_Depth int) {
+	if p_Tree.Compare != nil {
+		slices.SortStableFunc(p_Nodes,func(a, b *t_TreeNode) int { return p_Tree.Compare(reflect.ValueOf(a.label),reflect.ValueOf(b.label)) })
+	} // END if
+	for index,Node := range p_Nodes {
+		Node.Index, Node.Depth = index, p_Depth
+		Node.Children = len(Node.children)
+		Node.Leaf = Node.Children == 0
+		treeLink(p_Tree,Node.children,p_Depth+1)
+	} // END for
+} // END treeLink
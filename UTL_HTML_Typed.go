@@ -0,0 +1,217 @@
+package UTL_HTML
+//
+// UTL_HTML_Typed
+// Version: $Id$
+//
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"reflect"
+)
+
+// *************************************************************************************
+// Typed tables: The columns of a table are defined for a data-type T with accessor
+// functions, so they are checked at compile time and no reflection is needed to find them.
+//
+//  Cities := NewTable(
+//              T_Column[t_CityRec]{Header: "City", Value: func(c t_CityRec) any { return c.Name }},
+//              T_Column[t_CityRec]{Header: "Population", Value: func(c t_CityRec) any { return c.Population }, Align: "right"},
+//            )
+//  v_Doc.TableOpen().
+//          TheadOf(Cities).
+//          TbodyOf(Cities.Rows(CityList)).
+//        TagCloseTop()
+
+// Definition of a table column for the data-type T
+type T_Column[T any] struct {
+	Header      string          // content of <th>
+	HeaderClass string          // additional class-name of <th>
+	DataClass   string          // additional class-name of <td>
+	Style       string          // style attribute of <td>
+	Align       string          // text-align of <th> and <td>
+	Format      string          // fmt.Sprintf format of the value
+	Layout      string          // time.Format layout for time.Time values
+	Empty       string          // replacement for nil and zero values
+	Aggregate   string          // footer aggregate of Value, see TfootAggregates
+	Value       func(T) any     // the value of the cell
+	Render      func(T) string  // alternative to Value: the HTML content of the cell
+} // END T_Column
+
+// Definition of a table for the data-type T
+type T_Table[T any] struct {
+	TrClass   string        // class-name of <tr>
+	ThClass   string        // class-name of <th>
+	TdClass   string        // class-name of <td>
+	KeyHeader string        // header of the key column, only used for maps
+	Columns   []T_Column[T] // the columns of the table
+} // END T_Table
+
+// The header and the rows of a typed table, see TheadOf and TbodyOf
+type T_TableHead interface { renderHead(p_HTML *T_HTML) }
+type T_TableBody interface { renderBody(p_HTML *T_HTML) }
+
+// Return a new table definition with the columns
+func NewTable[T any](p_Columns ...T_Column[T]) *T_Table[T] {
+	for _,Column := range p_Columns {
+		if Column.Aggregate != "" {
+			parseAggregate(Column.Aggregate,"") // check the definition now, not while rendering
+		} // END if
+	} // END for
+	return &T_Table[T]{Columns: p_Columns}
+} // END NewTable
+
+// Append <thead> with the header-row of the table
+func (p_HTML *T_HTML) TheadOf(p_Table T_TableHead) *T_HTML {
+	p_HTML.TheadOpen()
+	p_Table.renderHead(p_HTML)
+	return p_HTML.TagCloseTop() // thead
+} // END TheadOf
+
+// Append <tbody> with the data-rows of the table
+func (p_HTML *T_HTML) TbodyOf(p_Rows T_TableBody) *T_HTML {
+	p_HTML.TbodyOpen()
+	p_Rows.renderBody(p_HTML)
+	return p_HTML.TagCloseTop() // tbody
+} // END TbodyOf
+
+// Return the rows of a slice; they are sorted and paginated like TrTdSlice, see Sortable and Paginate
+func (p_Table *T_Table[T]) Rows(p_Rows []T) T_TableBody {
+	return t_SliceBody[T]{p_Table,p_Rows}
+} // END Rows
+
+// Return the rows of a sequence, e.g. slices.Values() or a database cursor; they are rendered as they come
+func (p_Table *T_Table[T]) Seq(p_Rows iter.Seq[T]) T_TableBody {
+	return t_SeqBody[T]{p_Table,p_Rows}
+} // END Seq
+
+// Return the rows of a map, ordered by key, with the key column first if the table has a KeyHeader
+func MapRows[K cmp.Ordered, T any](p_Table *T_Table[T], p_Rows map[K]T) T_TableBody {
+	return t_MapBody[K,T]{p_Table,p_Rows}
+} // END MapRows
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+type t_SliceBody[T any] struct {
+	table *T_Table[T]
+	rows  []T
+} // END t_SliceBody
+
+type t_SeqBody[T any] struct {
+	table *T_Table[T]
+	rows  iter.Seq[T]
+} // END t_SeqBody
+
+type t_MapBody[K cmp.Ordered, T any] struct {
+	table *T_Table[T]
+	rows  map[K]T
+} // END t_MapBody
+
+func (p_Table *T_Table[T]) renderHead(p_HTML *T_HTML) {
+	p_HTML.helper_TrOpen(p_Table.TrClass)
+	p_HTML.headerStart()
+	if p_Table.KeyHeader != "" {
+		p_HTML.helper_SortTh(p_Table.KeyHeader,p_Table.KeyHeader,p_Table.ThClass,"","")
+	} // END if
+	for _,Column := range p_Table.Columns {
+		p_HTML.helper_SortTh(Column.Header,Column.Header,p_Table.ThClass,Column.HeaderClass,joinStyle("","text-align",Column.Align))
+	} // END for
+	p_HTML.TagCloseTop() // tr
+} // END renderHead
+
+// Append one data-row, p_Key is the content of the key column, if any
+func (p_Table *T_Table[T]) renderRow(p_HTML *T_HTML, p_Key *string, p_Row T) {
+	Cells := p_HTML.cellBuffer[:0]
+	if p_Key != nil {
+		Cells = append(Cells,t_Cell{name: p_Table.KeyHeader, value: reflect.ValueOf(*p_Key), content: *p_Key})
+	} // END if
+	for _,Column := range p_Table.Columns {
+		Cell := t_Cell{
+			name:  Column.Header,
+			class: Column.DataClass,
+			style: joinStyle(Column.Style,"text-align",Column.Align),
+			tag:   t_HtmlTag{Format: Column.Format, Layout: Column.Layout, Empty: Column.Empty, Aggregate: Column.Aggregate},
+		}
+		if Column.Value != nil {
+			Cell.value = reflect.ValueOf(Column.Value(p_Row))
+		} // END if
+		switch {
+			case Column.Render != nil: Cell.content = Column.Render(p_Row)
+			case Column.Value != nil: Cell.content = formatValue(Cell.value,Cell.tag)
+			default: Cell.content = "&nbsp;"
+		} // END switch
+		Cells = append(Cells,Cell)
+	} // END for
+	p_HTML.cellBuffer = Cells // reused by the next row
+	p_HTML.helper_TrTdCells(p_Table.TrClass,p_Table.TdClass,Cells)
+} // END renderRow
+
+// Sort the rows in place by the sort column, see Sortable; p_Offset is the number of columns before the table columns
+func sortTyped[E any](p_HTML *T_HTML, p_Table []T_Column[E], p_Offset int, p_Rows []E) {
+	Column := p_HTML.sortIndex() - p_Offset
+	if Column < 0 || Column >= len(p_Table) || p_Table[Column].Value == nil {
+		return
+	} // END if
+	Value := p_Table[Column].Value
+	slices.SortStableFunc(p_Rows,func(a,b E) int {
+		return p_HTML.sortCompare(reflect.ValueOf(Value(a)),reflect.ValueOf(Value(b)))
+	})
+} // END sortTyped
+
+func (p_Body t_SliceBody[T]) renderBody(p_HTML *T_HTML) {
+	Rows := slices.Clone(p_Body.rows)
+	sortTyped(p_HTML,p_Body.table.Columns,0,Rows)
+	for _,Row := range pageSlice(p_HTML,Rows) {
+		if p_HTML.Cancelled() { break } // client is gone
+		p_Body.table.renderRow(p_HTML,nil,Row)
+	} // END for
+	p_HTML.groupEnd(p_Body.table.TrClass,p_Body.table.TdClass)
+} // END renderBody
+
+func (p_Body t_SeqBody[T]) renderBody(p_HTML *T_HTML) {
+	for Row := range p_Body.rows {
+		if p_HTML.Cancelled() { break } // client is gone
+		p_Body.table.renderRow(p_HTML,nil,Row)
+	} // END for
+	p_HTML.groupEnd(p_Body.table.TrClass,p_Body.table.TdClass)
+} // END renderBody
+
+func (p_Body t_MapBody[K,T]) renderBody(p_HTML *T_HTML) {
+	Keys := slices.Sorted(func(yield func(K) bool) {
+		for Key := range p_Body.rows {
+			if !yield(Key) { return }
+		} // END for
+	})
+	Offset := 0
+	if p_Body.table.KeyHeader != "" {
+		Offset = 1
+		if p_HTML.sortIndex() == 0 {
+			slices.SortStableFunc(Keys,func(a,b K) int { return p_HTML.sortCompare(reflect.ValueOf(a),reflect.ValueOf(b)) })
+		} // END if
+	} // END if
+	Rows := make([]T,len(Keys)) // sort the keys along with the rows
+	for index,Key := range Keys {
+		Rows[index] = p_Body.rows[Key]
+	} // END for
+	Order := make([]int,len(Keys))
+	for index := range Order {
+		Order[index] = index
+	} // END for
+	if Column := p_HTML.sortIndex() - Offset; Column >= 0 && Column < len(p_Body.table.Columns) && p_Body.table.Columns[Column].Value != nil {
+		Value := p_Body.table.Columns[Column].Value
+		slices.SortStableFunc(Order,func(a,b int) int {
+			return p_HTML.sortCompare(reflect.ValueOf(Value(Rows[a])),reflect.ValueOf(Value(Rows[b])))
+		})
+	} // END if
+	for _,index := range pageSlice(p_HTML,Order) {
+		if p_HTML.Cancelled() { break } // client is gone
+		if Offset == 0 {
+			p_Body.table.renderRow(p_HTML,nil,Rows[index])
+		} else {
+			KeyText := formatValue(reflect.ValueOf(Keys[index]),t_HtmlTag{})
+			p_Body.table.renderRow(p_HTML,&KeyText,Rows[index])
+		} // END if
+	} // END for
+	p_HTML.groupEnd(p_Body.table.TrClass,p_Body.table.TdClass)
+} // END renderBody
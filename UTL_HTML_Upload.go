@@ -0,0 +1,307 @@
+package UTL_HTML
+//
+// UTL_HTML_Upload
+// Version: $Id$
+//
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// *************************************************************************************
+// File uploads: forms with a FileField are sent as multipart/form-data. ReadUploads parses
+// the body with a memory limit - larger files go to temporary files - and returns the
+// accepted files with name, size and the content type detected from the first 512 bytes.
+// SaveUploads streams the files directly into a directory instead, without holding them.
+// Files that are too large or of a type not allowed are returned as T_FieldErrors by the
+// name of their field, ready for FormErrors. The other form fields remain available for
+// BindRequest and ReadReqParameter.
+
+const (
+	GC_UploadMemory int64 = 32 << 20 // default memory limit of ReadUploads and ReadReqParameter
+
+	GC_UploadTooLarge string = "The file %s is larger than %s."
+	GC_UploadType     string = "Files of the type %s are not accepted."
+	GC_UploadBody     string = "The upload is larger than %s."
+) // END const
+
+// Limits of an upload
+type T_Upload struct {
+	MaxMemory int64    // ReadUploads: bytes kept in memory, the rest goes to temporary files; 0: GC_UploadMemory
+	MaxSize   int64    // maximum size of a file, 0: no limit
+	MaxBody   int64    // maximum size of the whole request body, 0: no limit
+	Types     []string // accepted content types, e.g. "image/png", "image/*", "application/pdf"; empty: all types
+} // END T_Upload
+
+// An uploaded file
+type T_UploadFile struct {
+	Field  string // name of the form field
+	Name   string // name of the file on the client, without directories
+	Size   int64  // size in bytes
+	Type   string // content type detected from the content, e.g. "image/png"
+	Path   string // SaveUploads: the saved file
+	header *multipart.FileHeader
+} // END T_UploadFile
+
+// Open the content of a file of ReadUploads or SaveUploads
+func (p_File T_UploadFile) Open() (io.ReadCloser, error) {
+	if p_File.header != nil {
+		return p_File.header.Open()
+	} // END if
+	return os.Open(p_File.Path)
+} // END Open
+
+// Parse a multipart/form-data request and return the accepted files, sorted by field. Files that break
+// the limits are left out and reported by the name of their field, errors of the request by the name "".
+// A body already parsed by ParseMultipartForm, e.g. by FormValue, is refused: its limits are unknown.
+func ReadUploads(r *http.Request, p_Upload T_Upload) ([]T_UploadFile, T_FieldErrors) {
+	if r.MultipartForm != nil {
+		return nil, T_FieldErrors{"": v_UploadParsed.Error()}
+	} // END if
+	if p_Upload.MaxBody > 0 {
+		r.Body = http.MaxBytesReader(nil,r.Body,p_Upload.MaxBody)
+	} // END if
+	Memory := p_Upload.MaxMemory
+	if Memory <= 0 {
+		Memory = GC_UploadMemory
+	} // END if
+	if err := r.ParseMultipartForm(Memory); err != nil {
+		return nil, T_FieldErrors{"": uploadError(err,p_Upload)}
+	} // END if
+
+	Fields := make([]string,0,len(r.MultipartForm.File))
+	for Field := range r.MultipartForm.File {
+		Fields = append(Fields,Field)
+	} // END for
+	sort.Strings(Fields)
+	var Files []T_UploadFile
+	var Errors T_FieldErrors
+	for _,Field := range Fields {
+		for _,Header := range r.MultipartForm.File[Field] {
+			File := T_UploadFile{Field: Field, Name: uploadName(Header.Filename), Size: Header.Size, header: Header}
+			Content,err := Header.Open()
+			if err == nil {
+				File.Type,err = uploadType(Content)
+				Content.Close()
+			} // END if
+			Message := ""
+			switch {
+				case err != nil: Message = err.Error()
+				case p_Upload.MaxSize > 0 && File.Size > p_Upload.MaxSize: Message = fmt.Sprintf(GC_UploadTooLarge,File.Name,uploadSize(p_Upload.MaxSize))
+				case !uploadAccepted(File.Type,p_Upload.Types): Message = fmt.Sprintf(GC_UploadType,File.Type)
+			} // END switch
+			if Message != "" {
+				Errors = Errors.Merge(T_FieldErrors{Field: Message})
+				continue
+			} // END if
+			Files = append(Files,File)
+		} // END for
+	} // END for
+	return Files, Errors
+} // END ReadUploads
+
+// Stream the files of a multipart/form-data request into the directory p_Dir, with names generated by
+// os.CreateTemp and the extension of the client's file name, and return them in the order of the request.
+// Files that break the limits are not saved and reported by the name of their field. The other fields are
+// stored in r.Form and r.PostForm for BindRequest and ReadReqParameter. If the request can't be read,
+// the files saved so far are removed and the error is returned by the name "", as for a parsed body, see ReadUploads.
+func SaveUploads(r *http.Request, p_Upload T_Upload, p_Dir string) ([]T_UploadFile, T_FieldErrors) {
+	if r.MultipartForm != nil {
+		return nil, T_FieldErrors{"": v_UploadParsed.Error()}
+	} // END if
+	if p_Upload.MaxBody > 0 {
+		r.Body = http.MaxBytesReader(nil,r.Body,p_Upload.MaxBody)
+	} // END if
+	Reader,err := r.MultipartReader()
+	if err != nil {
+		return nil, T_FieldErrors{"": err.Error()}
+	} // END if
+
+	var Files []T_UploadFile
+	var Errors T_FieldErrors
+	Values := make(url.Values)
+	Fail := func(p_Error error) ([]T_UploadFile, T_FieldErrors) {
+		for _,File := range Files {
+			os.Remove(File.Path)
+		} // END for
+		return nil, T_FieldErrors{"": uploadError(p_Error,p_Upload)}
+	} // END Fail
+	for {
+		Part,err := Reader.NextPart()
+		if err == io.EOF {
+			break
+		} // END if
+		if err != nil {
+			return Fail(err)
+		} // END if
+		Field := Part.FormName()
+		if Part.FileName() == "" { // an ordinary form field
+			Value,err := io.ReadAll(io.LimitReader(Part,GC_UploadMemory))
+			if err != nil {
+				return Fail(err)
+			} // END if
+			Values.Add(Field,string(Value))
+			continue
+		} // END if
+		File,Message,err := uploadSave(Part,p_Upload,p_Dir)
+		if err != nil {
+			return Fail(err)
+		} // END if
+		if Message != "" {
+			Errors = Errors.Merge(T_FieldErrors{Field: Message})
+			continue
+		} // END if
+		Files = append(Files,File)
+	} // END for
+
+	r.PostForm = Values
+	r.Form = make(url.Values)
+	for Name,List := range r.URL.Query() {
+		r.Form[Name] = List
+	} // END for
+	for Name,List := range Values {
+		r.Form[Name] = append(List,r.Form[Name]...) // body values first, as ParseForm does
+	} // END for
+	return Files, Errors
+} // END SaveUploads
+
+// Append a file input that accepts the Types of the upload, see FileField
+func (p_HTML *T_HTML) UploadField(p_name string, p_Upload T_Upload, p_multiple bool, p_Attributes ...string) *T_HTML {
+	return p_HTML.FileField(p_name,strings.Join(p_Upload.Types,","),p_multiple,p_Attributes...)
+} // END UploadField
+
+func UploadField(p_name string, p_Upload T_Upload, p_multiple bool, p_Attributes ...string) string {
+	return FileField(p_name,strings.Join(p_Upload.Types,","),p_multiple,p_Attributes...)
+} // END UploadField
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+var regExp_UploadExt = regexp.MustCompile(`^\.[A-Za-z0-9]{1,10}$`)
+
+var v_UploadParsed = errors.New("the request body was parsed before the upload, call ReadUploads or SaveUploads before FormValue and ParseMultipartForm")
+
+// Parse the URL and form values of a request, multipart/form-data too
+func parseForm(r *http.Request) error {
+	if r.MultipartForm != nil {
+		return nil // parsed by ReadUploads or SaveUploads
+	} // END if
+	if err := r.ParseMultipartForm(GC_UploadMemory); err != nil && !errors.Is(err,http.ErrNotMultipart) {
+		return err
+	} // END if
+	return nil
+} // END parseForm
+
+// Save one file of SaveUploads; a message if the file breaks the limits, an error if the request or the directory fails
+func uploadSave(p_Part *multipart.Part, p_Upload T_Upload, p_Dir string) (T_UploadFile, string, error) {
+	File := T_UploadFile{Field: p_Part.FormName(), Name: uploadName(p_Part.FileName())}
+	Head := make([]byte,512)
+	Length,err := io.ReadFull(p_Part,Head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return File, "", err
+	} // END if
+	Head = Head[:Length]
+	File.Type = http.DetectContentType(Head)
+	if !uploadAccepted(File.Type,p_Upload.Types) {
+		return File, fmt.Sprintf(GC_UploadType,File.Type), nil
+	} // END if
+
+	Extension := filepath.Ext(File.Name)
+	if !regExp_UploadExt.MatchString(Extension) {
+		Extension = ""
+	} // END if
+	Target,err := os.CreateTemp(p_Dir,"upload-*"+Extension)
+	if err != nil {
+		return File, "", err
+	} // END if
+	File.Path = Target.Name()
+	var Reader io.Reader = p_Part
+	if p_Upload.MaxSize > 0 {
+		Reader = io.LimitReader(p_Part,p_Upload.MaxSize-int64(Length)+1) // one byte more shows that the file is too large
+	} // END if
+	Written,err := Target.Write(Head)
+	if err == nil {
+		var Rest int64
+		Rest,err = io.Copy(Target,Reader)
+		File.Size = int64(Written) + Rest
+	} // END if
+	if CloseErr := Target.Close(); err == nil {
+		err = CloseErr
+	} // END if
+	switch {
+		case err != nil: {
+			os.Remove(File.Path)
+			return File, "", err
+		} // END case
+		case p_Upload.MaxSize > 0 && File.Size > p_Upload.MaxSize: {
+			os.Remove(File.Path)
+			return File, fmt.Sprintf(GC_UploadTooLarge,File.Name,uploadSize(p_Upload.MaxSize)), nil
+		} // END case
+	} // END switch
+	return File, "", nil
+} // END uploadSave
+
+// Detect the content type from the first 512 bytes
+func uploadType(p_Content io.Reader) (string, error) {
+	Head := make([]byte,512)
+	Length,err := io.ReadFull(p_Content,Head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	} // END if
+	return http.DetectContentType(Head[:Length]), nil
+} // END uploadType
+
+// The content type is one of the accepted types; "image/*" accepts all images, parameters like charset are ignored
+func uploadAccepted(p_Type string, p_Types []string) bool {
+	if len(p_Types) == 0 {
+		return true
+	} // END if
+	MediaType,_,err := mime.ParseMediaType(p_Type)
+	if err != nil {
+		MediaType = p_Type
+	} // END if
+	return slices.ContainsFunc(p_Types,func(p_Accepted string) bool {
+		p_Accepted = strings.ToLower(strings.TrimSpace(p_Accepted))
+		if Prefix,ok := strings.CutSuffix(p_Accepted,"/*"); ok {
+			return strings.HasPrefix(MediaType,Prefix+"/")
+		} // END if
+		return MediaType == p_Accepted
+	})
+} // END uploadAccepted
+
+// The file name of the client without directories, also those of Windows clients
+func uploadName(p_Name string) string {
+	if index := strings.LastIndexAny(p_Name,`/\`); index >= 0 {
+		p_Name = p_Name[index+1:]
+	} // END if
+	return p_Name
+} // END uploadName
+
+// Message of an error of the request, a body that is too large is reported with the limit
+func uploadError(p_Error error, p_Upload T_Upload) string {
+	var TooLarge *http.MaxBytesError
+	if errors.As(p_Error,&TooLarge) {
+		return fmt.Sprintf(GC_UploadBody,uploadSize(p_Upload.MaxBody))
+	} // END if
+	return p_Error.Error()
+} // END uploadError
+
+// A size in bytes for messages: 512 bytes, 64 KB, 2 MB
+func uploadSize(p_Size int64) string {
+	switch {
+		case p_Size >= 1<<20 && p_Size % (1<<20) == 0: return fmt.Sprintf("%d MB",p_Size>>20)
+		case p_Size >= 1<<10 && p_Size % (1<<10) == 0: return fmt.Sprintf("%d KB",p_Size>>10)
+	} // END switch
+	return fmt.Sprintf("%d bytes",p_Size)
+} // END uploadSize
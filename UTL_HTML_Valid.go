@@ -0,0 +1,349 @@
+package UTL_HTML
+//
+// UTL_HTML_Valid
+// Version: $Id$
+//
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+	"net/url"
+	"net/mail"
+	"unicode/utf8"
+)
+
+// *************************************************************************************
+// Form validation: Validate checks a struct, usually after BindRequest, against the rules
+// of the html struct-tag and against checks declared in code, and returns the errors by
+// parameter name. FormErrors hands the errors and the values the user typed to the form
+// fields: fields with errors get the class "invalid", aria-invalid and a message element,
+// FormFields shows the typed values again and adds the HTML5 constraint attributes.
+//
+//  Required                → a value, a checked checkbox, at least one element of a slice
+//  MinLength, MaxLength    → number of characters of text
+//  Min, Max                → range of numbers and dates
+//  Pattern                 → regular expression for the whole value, like the pattern attribute
+//  Input='email' / 'url'   → email address / absolute URL
+//  Options                 → one of the options
+//
+// Except Required, the rules are only checked for values that are not empty.
+
+// Messages of the validation rules
+const (
+	GC_ValidRequired  string = "Please fill in this field."
+	GC_ValidMinLength string = "Please use at least %d characters."
+	GC_ValidMaxLength string = "Please use at most %d characters."
+	GC_ValidMin       string = "The value must be %s or more."
+	GC_ValidMax       string = "The value must be %s or less."
+	GC_ValidPattern   string = "Please match the requested format."
+	GC_ValidEmail     string = "Please enter an email address."
+	GC_ValidURL       string = "Please enter a URL."
+	GC_ValidOneOf     string = "Please select one of the options."
+) // END const
+
+// Classes of fields with errors and their messages
+const (
+	GC_ClassInvalid     string = "invalid"         // class of the field
+	GC_ClassInvalidText string = "invalid-message" // class of the message element
+) // END const
+
+// A check declared in code: it gets the struct and adds its errors, e.g. for rules across fields
+type T_Check func(p_Data any, p_Errors T_FieldErrors)
+
+// Validate a struct{} or *struct{}: first the rules of the html struct-tags, then the checks.
+// Return the errors by parameter name, nil if there are none; a field keeps its first error.
+func Validate(p_Data any, p_Checks ...T_Check) T_FieldErrors {
+	Struct := derefValue(reflect.ValueOf(p_Data))
+	if Struct.Kind() != reflect.Struct { panic(fmt.Sprintf("Unknown datatype used in Validate: %T",p_Data)) }
+
+	Errors := make(T_FieldErrors)
+	for _,Column := range structMeta(Struct.Type()).Columns {
+		if Message := validateColumn(Column,Column.value(Struct)); Message != "" {
+			Errors.Add(Column.param(),Message)
+		} // END if
+	} // END for
+	for _,Check := range p_Checks {
+		Check(p_Data,Errors)
+	} // END for
+	if len(Errors) == 0 {
+		return nil
+	} // END if
+	return Errors
+} // END Validate
+
+// Add an error, the first error of a field is kept
+func (p_Errors T_FieldErrors) Add(p_Name, p_Message string) {
+	if _,ok := p_Errors[p_Name]; !ok {
+		p_Errors[p_Name] = p_Message
+	} // END if
+} // END Add
+
+// Add the errors of another set, e.g. the errors of BindRequest and Validate; the receiver may be nil
+func (p_Errors T_FieldErrors) Merge(p_Other T_FieldErrors) T_FieldErrors {
+	for Name,Message := range p_Other {
+		if p_Errors == nil {
+			p_Errors = make(T_FieldErrors)
+		} // END if
+		p_Errors.Add(Name,Message)
+	} // END for
+	return p_Errors
+} // END Merge
+
+// Check of a field with a function returning the message, "" if the value is valid; the value is dereferenced, nil for nil pointers
+func CheckField(p_Name string, p_Check func(p_Value any) string) T_Check {
+	return func(p_Data any, p_Errors T_FieldErrors) {
+		Value := sqlValue(checkValue(p_Data,p_Name))
+		var Data any
+		if Value.IsValid() {
+			Data = Value.Interface()
+		} // END if
+		if Message := p_Check(Data); Message != "" {
+			p_Errors.Add(p_Name,Message)
+		} // END if
+	}
+} // END CheckField
+
+// The fields are required
+func CheckRequired(p_Names ...string) T_Check {
+	return func(p_Data any, p_Errors T_FieldErrors) {
+		for _,Name := range p_Names {
+			if checkEmpty(checkValue(p_Data,Name)) {
+				p_Errors.Add(Name,GC_ValidRequired)
+			} // END if
+		} // END for
+	}
+} // END CheckRequired
+
+// Number of characters of a text field, 0: no limit
+func CheckLength(p_Name string, p_Min, p_Max int) T_Check {
+	return checkRule(p_Name,t_HtmlTag{MinLength: strconv.Itoa(p_Min), MaxLength: strconv.Itoa(p_Max)})
+} // END CheckLength
+
+// Range of a number or date field, empty: no limit; dates as 2006-01-02
+func CheckRange(p_Name string, p_Min, p_Max string) T_Check {
+	return checkRule(p_Name,t_HtmlTag{Min: p_Min, Max: p_Max})
+} // END CheckRange
+
+// The whole value matches the regular expression
+func CheckPattern(p_Name, p_Pattern string) T_Check {
+	checkRegexp(p_Pattern) // check the pattern now, not while validating
+	return checkRule(p_Name,t_HtmlTag{Pattern: p_Pattern})
+} // END CheckPattern
+
+// The value is an email address
+func CheckEmail(p_Name string) T_Check {
+	return checkRule(p_Name,t_HtmlTag{Input: "email"})
+} // END CheckEmail
+
+// The value, or every element of a slice, is one of the values
+func CheckOneOf(p_Name string, p_Values ...string) T_Check {
+	return checkRule(p_Name,t_HtmlTag{Options: strings.Join(p_Values,",")})
+} // END CheckOneOf
+
+// Two fields have the same value, e.g. a password and its confirmation; the error is added to the second field
+func CheckEqual(p_Name, p_Other, p_Message string) T_Check {
+	return func(p_Data any, p_Errors T_FieldErrors) {
+		if groupText(sqlValue(checkValue(p_Data,p_Name))) != groupText(sqlValue(checkValue(p_Data,p_Other))) {
+			p_Errors.Add(p_Other,p_Message)
+		} // END if
+	}
+} // END CheckEqual
+
+// Show errors in the form fields: the form controls, SelectMenu, HiddenField and FormFields add the class
+// GC_ClassInvalid, aria-invalid and aria-describedby to fields with errors and append the message as
+// <span class="invalid-message" id="name-error">. p_Values are the values the user typed, usually r.Form;
+// FormFields shows them instead of the values of the struct for fields with errors. Active until FormErrorsEnd.
+func (p_HTML *T_HTML) FormErrors(p_Errors T_FieldErrors, p_Values url.Values) *T_HTML {
+	p_HTML.formErrors = p_Errors
+	p_HTML.formValues = p_Values
+	return p_HTML
+} // END FormErrors
+
+// Stop showing errors in the form fields
+func (p_HTML *T_HTML) FormErrorsEnd() *T_HTML {
+	p_HTML.formErrors = nil
+	p_HTML.formValues = nil
+	return p_HTML
+} // END FormErrorsEnd
+
+// -------------------------------------------------------------------
+// not exported implementation
+
+var v_RegexpCache sync.Map // pattern → *regexp.Regexp
+
+// Check of a field by the rules of a struct-tag
+func checkRule(p_Name string, p_Tag t_HtmlTag) T_Check {
+	return func(p_Data any, p_Errors T_FieldErrors) {
+		Value := checkValue(p_Data,p_Name)
+		if Message := validateColumn(t_StructColumn{Tag: p_Tag},Value); Message != "" {
+			p_Errors.Add(p_Name,Message)
+		} // END if
+	}
+} // END checkRule
+
+// Value of a field by parameter name, invalid if there is no such field
+func checkValue(p_Data any, p_Name string) reflect.Value {
+	Struct := derefValue(reflect.ValueOf(p_Data))
+	if Struct.Kind() != reflect.Struct {
+		return reflect.Value{}
+	} // END if
+	for _,Column := range structMeta(Struct.Type()).Columns {
+		if Column.param() == p_Name {
+			return Column.value(Struct)
+		} // END if
+	} // END for
+	return reflect.Value{}
+} // END checkValue
+
+// The value is empty: nil, blank text, an unchecked checkbox, an empty slice or a zero time
+func checkEmpty(p_Value reflect.Value) bool {
+	Value := sqlValue(p_Value)
+	switch {
+		case !Value.IsValid(): return true
+		case Value.Kind() == reflect.String: return strings.TrimSpace(Value.String()) == ""
+		case Value.Kind() == reflect.Bool: return !Value.Bool()
+		case Value.Kind() == reflect.Slice || Value.Kind() == reflect.Map: return Value.Len() == 0
+		case Value.Type() == v_TimeType: return Value.IsZero()
+	} // END switch
+	return false
+} // END checkEmpty
+
+// Compiled regular expression for the whole value, cached
+func checkRegexp(p_Pattern string) *regexp.Regexp {
+	if RegExp,ok := v_RegexpCache.Load(p_Pattern); ok {
+		return RegExp.(*regexp.Regexp)
+	} // END if
+	RegExp,_ := v_RegexpCache.LoadOrStore(p_Pattern,regexp.MustCompile("^(?:" + p_Pattern + ")$"))
+	return RegExp.(*regexp.Regexp)
+} // END checkRegexp
+
+// Check a value against the rules of the struct-tag of its column, return the message of the first broken rule
+func validateColumn(p_Column t_StructColumn, p_Value reflect.Value) string {
+	Tag := p_Column.Tag
+	if checkEmpty(p_Value) {
+		if Tag.Required {
+			return GC_ValidRequired
+		} // END if
+		return ""
+	} // END if
+	Value := sqlValue(p_Value)
+	Values := []reflect.Value{Value}
+	if Value.Kind() == reflect.Slice && Value.Type().Elem().Kind() != reflect.Uint8 {
+		Values = Values[:0]
+		for index := 0; index < Value.Len(); index++ {
+			Values = append(Values,sqlValue(Value.Index(index)))
+		} // END for
+	} // END if
+	for _,Element := range Values {
+		if Message := validateValue(Tag,Element); Message != "" {
+			return Message
+		} // END if
+	} // END for
+	return ""
+} // END validateColumn
+
+// Check one value that is not empty
+func validateValue(p_Tag t_HtmlTag, p_Value reflect.Value) string {
+	if !p_Value.IsValid() {
+		return ""
+	} // END if
+	Text := groupText(p_Value)
+	Length := utf8.RuneCountInString(Text)
+	if Min,err := strconv.Atoi(p_Tag.MinLength); err == nil && Min > 0 && Length < Min {
+		return fmt.Sprintf(GC_ValidMinLength,Min)
+	} // END if
+	if Max,err := strconv.Atoi(p_Tag.MaxLength); err == nil && Max > 0 && Length > Max {
+		return fmt.Sprintf(GC_ValidMaxLength,Max)
+	} // END if
+	if p_Tag.Min != "" && checkCompare(p_Value,p_Tag.Min) < 0 {
+		return fmt.Sprintf(GC_ValidMin,p_Tag.Min)
+	} // END if
+	if p_Tag.Max != "" && checkCompare(p_Value,p_Tag.Max) > 0 {
+		return fmt.Sprintf(GC_ValidMax,p_Tag.Max)
+	} // END if
+	if p_Tag.Pattern != "" && !checkRegexp(p_Tag.Pattern).MatchString(Text) {
+		return GC_ValidPattern
+	} // END if
+	switch strings.ToLower(p_Tag.Input) {
+		case "email": {
+			if Address,err := mail.ParseAddress(Text); err != nil || Address.Address != Text {
+				return GC_ValidEmail
+			} // END if
+		} // END case
+		case "url": {
+			if URL,err := url.ParseRequestURI(Text); err != nil || URL.Scheme == "" || URL.Host == "" {
+				return GC_ValidURL
+			} // END if
+		} // END case
+	} // END switch
+	if p_Tag.Options != "" && !slices.ContainsFunc(formOptions(p_Tag.Options),func(p_Option t_FormOption) bool { return p_Option.value == Text }) {
+		return GC_ValidOneOf
+	} // END if
+	return ""
+} // END validateValue
+
+// Compare a number or date with a limit of the struct-tag: -1, 0, +1; 0 if they can't be compared
+func checkCompare(p_Value reflect.Value, p_Limit string) int {
+	if Time,ok := p_Value.Interface().(time.Time); ok {
+		for _,Layout := range v_BindTimeLayouts {
+			if Limit,err := time.ParseInLocation(Layout,p_Limit,Time.Location()); err == nil {
+				return Time.Compare(Limit)
+			} // END if
+		} // END for
+		return 0
+	} // END if
+	Number,ok := numericValue(p_Value)
+	Limit,okLimit := parseNumber(p_Limit)
+	switch {
+		case !ok || !okLimit: return 0
+		case Number < Limit: return -1
+		case Number > Limit: return 1
+	} // END switch
+	return 0
+} // END checkCompare
+
+// Add the error attributes to the attributes of a field with an error: the class GC_ClassInvalid, aria-invalid and aria-describedby
+func (p_HTML *T_HTML) formInvalid(p_Name string, p_Attributes []string) []string {
+	if _,ok := p_HTML.formErrors[p_Name]; !ok || p_Name == "" {
+		return p_Attributes
+	} // END if
+	Attributes := make([]string,0,len(p_Attributes)+6)
+	Class := false
+	for index := 0; index+1 < len(p_Attributes); index += 2 {
+		if p_Attributes[index] == "class" && !Class {
+			Attributes = append(Attributes,"class",strings.TrimSpace(p_Attributes[index+1]+" "+GC_ClassInvalid))
+			Class = true
+			continue
+		} // END if
+		Attributes = append(Attributes,p_Attributes[index],p_Attributes[index+1])
+	} // END for
+	if len(p_Attributes) % 2 == 1 {
+		Attributes = append(Attributes,p_Attributes[len(p_Attributes)-1]) // attribute without value
+	} // END if
+	if !Class {
+		Attributes = append(Attributes,"class",GC_ClassInvalid)
+	} // END if
+	return append(Attributes,"aria-invalid","true","aria-describedby",p_Name+"-error")
+} // END formInvalid
+
+// Append the message of a field with an error
+func (p_HTML *T_HTML) formMessage(p_Name string) *T_HTML {
+	if Message,ok := p_HTML.formErrors[p_Name]; ok && p_Name != "" {
+		p_HTML.Tag("span",html.EscapeString(Message),"class",GC_ClassInvalidText,"id",p_Name+"-error")
+	} // END if
+	return p_HTML
+} // END formMessage
+
+// The values the user typed for a field with an error, nil for fields without error
+func (p_HTML *T_HTML) formTyped(p_Name string) []string {
+	if _,ok := p_HTML.formErrors[p_Name]; !ok {
+		return nil
+	} // END if
+	return p_HTML.formValues[p_Name]
+} // END formTyped
@@ -0,0 +1,518 @@
+package UTL_HTML
+// -------------------------------------------------------------------------------------------------
+
+//
+// -------------------------------------------------------------------------------------------------
+
+import (
+  "io"
+  "fmt"
+  "os"
+  "time"
+  "bytes"
+  "mime/multipart"
+  "slices"
+  "strings"
+  "testing"
+  "net/url"
+  "net/http"
+  "database/sql"
+  "net/http/httptest"
+)
+
+/* */
+
+// *****************************************************
+// A struct for editing: the type of the form fields is derived from the field types
+// *****************************************************
+type t_ShipTo struct {
+  Street string
+  City   string `html:"Required"`
+} // END t_ShipTo
+
+type t_DuckOrder struct {
+  Id       int       `html:"Input='hidden'"`
+  Customer string    `html:"Label='Your name' Placeholder='First and last name' Required"`
+  Email    string    `html:"Input='email'"`
+  Quantity int
+  Price    float64
+  Express  bool
+  Delivery time.Time
+  Breeds   []string  `html:"Options='PK=Pekin,RN=Rouen,CA=Cayuga'"`
+  Size     string    `html:"Options='S=Small,M=Medium,L=Large'"`
+  Note     sql.NullString `html:"Input='textarea'"`
+  Address  t_ShipTo
+  Internal string    `html:"Skip"`
+} // END t_DuckOrder
+
+func Test_FormStruct(t *testing.T) {
+  Order := t_DuckOrder{Id: 7, Customer: `Fred "Duck" Jones`, Quantity: 3, Price: 12.5, Express: true,
+                       Delivery: time.Date(2025,6,1,0,0,0,0,time.UTC), Breeds: []string{"PK","CA"}, Size: "M",
+                       Note: sql.NullString{String: "<ring twice>", Valid: true}, Address: t_ShipTo{City: "Leipzig"}}
+  Doc := New(GC_DocTypeNONE,0x00).FormStruct("/order","get","field","Send",&Order).String()
+  Expected := []string{
+    `<form action="/order" method="get"><input type="hidden" name="Id" value="7" id="Id" />`,
+    `<div class="field"><label for="Customer">Your name</label><input type="text" id="Customer" name="Customer" placeholder="First and last name" required="required" value="Fred &#34;Duck&#34; Jones" /></div>`,
+    `<input type="email" id="Email" name="Email" />`,
+    `<input type="number" id="Quantity" name="Quantity" value="3" />`,
+    `<input type="number" id="Price" name="Price" step="any" value="12.5" />`,
+    `<input type="checkbox" id="Express" name="Express" value="true" checked="checked" />`,
+    `<input type="date" id="Delivery" name="Delivery" value="2025-06-01" />`,
+    `<select id="Breeds" name="Breeds" multiple="multiple"><option value="PK" selected="selected">Pekin</option><option value="RN">Rouen</option><option value="CA" selected="selected">Cayuga</option></select>`,
+    `<select id="Size" name="Size"><option value="S">Small</option><option value="M" selected="selected">Medium</option><option value="L">Large</option></select>`,
+    `<textarea id="Note" name="Note">&lt;ring twice&gt;</textarea>`,
+    `<fieldset><legend>Address</legend><div class="field"><label for="Address.Street">Street</label><input type="text" id="Address.Street" name="Address.Street" /></div>`,
+    `<input type="text" id="Address.City" name="Address.City" required="required" value="Leipzig" /></div></fieldset><button type="submit">Send</button></form>`,
+  }
+  for _,Fragment := range Expected {
+    if !strings.Contains(Doc,Fragment) {
+      t.Errorf("FormStruct: »%s« not found in »%s«",Fragment,Doc)
+    } // END if
+  } // END for
+  if strings.Contains(Doc,"Internal") {
+    t.Errorf("FormStruct: skipped field in »%s«",Doc)
+  } // END if
+} // END Test_FormStruct
+
+/* */
+
+// *****************************************************
+// Testing the binding of request parameters into the struct of the form
+// *****************************************************
+type t_DuckFilter struct {
+  Query    string    `html:"Param='q'"`
+  Page     *int
+  Flying   *bool
+  Since    time.Time `html:"Layout='02.01.2006'"`
+  Weight   float32
+} // END t_DuckFilter
+
+func Test_BindParameters(t *testing.T) {
+  Order := t_DuckOrder{Internal: "keep", Size: "S", Express: true}
+  Values := url.Values{
+    "Id":           {" 7 "},
+    "Customer":     {" Fred "},
+    "Quantity":     {"three"},
+    "Price":        {"12.5"},
+    "Delivery":     {"2025-06-01"},
+    "Breeds":       {"PK","CA"},
+    "Note":         {"ring twice"},
+    "Address.City": {"Leipzig"},
+    "Internal":     {"changed"},
+  }
+  Errors := BindParameters(Values,&Order)
+  if len(Errors) != 1 || Errors["Quantity"] != `"three" is not a whole number` {
+    t.Errorf("BindParameters: unexpected errors %v",Errors)
+  } // END if
+  switch {
+    case Order.Id != 7 || Order.Customer != " Fred " || Order.Price != 12.5: t.Errorf("BindParameters: unexpected values %+v",Order)
+    case Order.Express: t.Errorf("BindParameters: missing checkbox must be false")
+    case Order.Size != "S" || Order.Internal != "keep": t.Errorf("BindParameters: missing and skipped fields must be unchanged %+v",Order)
+    case Order.Delivery.Format("2006-01-02") != "2025-06-01": t.Errorf("BindParameters: unexpected date %v",Order.Delivery)
+    case !slices.Equal(Order.Breeds,[]string{"PK","CA"}): t.Errorf("BindParameters: unexpected multi-values %v",Order.Breeds)
+    case !Order.Note.Valid || Order.Note.String != "ring twice": t.Errorf("BindParameters: unexpected sql.NullString %v",Order.Note)
+    case Order.Address.City != "Leipzig": t.Errorf("BindParameters: unexpected nested field %+v",Order.Address)
+  } // END switch
+
+  // a nil *struct with a bool field stays nil if none of its parameters is sent
+  type Options struct{ GiftWrap bool }
+  type t_Gift struct {
+    *Options
+    Note string
+  }
+  Gift := t_Gift{}
+  if Errors := BindParameters(url.Values{"Note": {"  Happy  birthday "}},&Gift); Errors != nil || Gift.Options != nil || Gift.Note != "  Happy  birthday " {
+    t.Errorf("BindParameters: unexpected gift %+v %v",Gift,Errors)
+  } // END if
+  if Errors := BindParameters(url.Values{"GiftWrap": {"on"}},&Gift); Errors != nil || Gift.Options == nil || !Gift.GiftWrap {
+    t.Errorf("BindParameters: the sent bool field must allocate the struct %+v %v",Gift,Errors)
+  } // END if
+
+  // the request of a search form: Param of the struct-tag, optional fields, Layout
+  Request := httptest.NewRequest("GET","/ducks?q=Pekin&Flying=on&Page=&Since=24.12.2024&Weight=1.5e0",nil)
+  var Filter t_DuckFilter
+  if Errors := BindRequest(Request,&Filter); Errors != nil {
+    t.Errorf("BindRequest: unexpected errors %v",Errors)
+  } // END if
+  if Filter.Query != "Pekin" || Filter.Page != nil || Filter.Flying == nil || !*Filter.Flying || Filter.Since.Format("2006-01-02") != "2024-12-24" || Filter.Weight != 1.5 {
+    t.Errorf("BindRequest: unexpected values %+v",Filter)
+  } // END if
+  Request = httptest.NewRequest("GET","/ducks?Flying=maybe&Since=tomorrow",nil)
+  if Errors := BindRequest(Request,&Filter); Errors.Error() != `Flying: "maybe" is not a yes/no value; Since: "tomorrow" is not a date or time` {
+    t.Errorf("BindRequest: unexpected errors »%v«",Errors)
+  } // END if
+} // END Test_BindParameters
+
+/* */
+
+// *****************************************************
+// Testing the validation of a bound struct and the form with the errors and the typed values
+// *****************************************************
+type t_DuckSignup struct {
+  Name     string    `html:"Required MinLength='3' MaxLength='20'"`
+  Email    string    `html:"Input='email' Required"`
+  Age      int       `html:"Min='1' Max='30'"`
+  Code     string    `html:"Pattern='[A-Z]{2}-[0-9]+'"`
+  Breed    string    `html:"Options='PK=Pekin,RN=Rouen'"`
+  Password string    `html:"Input='password'"`
+  Repeat   string    `html:"Input='password'"`
+} // END t_DuckSignup
+
+func Test_Validate(t *testing.T) {
+  Signup := t_DuckSignup{Name: "Fred", Email: "fred@example.org", Age: 3, Code: "DE-42", Breed: "PK", Password: "quack", Repeat: "quack"}
+  Checks := []T_Check{CheckEqual("Password","Repeat","The passwords differ."), CheckLength("Password",5,0)}
+  if Errors := Validate(&Signup,Checks...); Errors != nil {
+    t.Errorf("Validate: unexpected errors %v",Errors)
+  } // END if
+
+  Values := url.Values{"Name": {"Fr"}, "Email": {"fred"}, "Age": {"42"}, "Code": {"de-42"}, "Breed": {"XX"}, "Password": {"quack"}, "Repeat": {"quak"}}
+  Signup = t_DuckSignup{}
+  Errors := BindParameters(Values,&Signup).Merge(Validate(&Signup,Checks...))
+  Expected := T_FieldErrors{
+    "Name":   "Please use at least 3 characters.",
+    "Email":  GC_ValidEmail,
+    "Age":    "The value must be 30 or less.",
+    "Code":   GC_ValidPattern,
+    "Breed":  GC_ValidOneOf,
+    "Repeat": "The passwords differ.",
+  }
+  if Errors.Error() != Expected.Error() {
+    t.Errorf("Validate: expected »%v« got »%v«",Expected,Errors)
+  } // END if
+  if Errors := Validate(t_DuckSignup{},CheckRequired("Age"),CheckField("Code",func(p_Value any) string { return "never" })); Errors.Error() != "Age: The value must be 1 or more.; Code: never; Email: Please fill in this field.; Name: Please fill in this field." {
+    t.Errorf("Validate: unexpected errors for empty values »%v«",Errors)
+  } // END if
+
+  // a quote within the pattern, a broken pattern panics before the validation
+  type t_Nick struct {
+    Nick string `html:"Pattern='[^\\']*'"`
+  } // END t_Nick
+  if Errors := Validate(t_Nick{Nick: "Donald's"}); Errors["Nick"] != GC_ValidPattern || Validate(t_Nick{Nick: "Donald"}) != nil {
+    t.Errorf("Validate: quote in the pattern not respected »%v«",Errors)
+  } // END if
+  type t_Broken struct {
+    Nick string `html:"Pattern='[A-Z'"`
+  } // END t_Broken
+  func() {
+    defer func() {
+      if recover() == nil {
+        t.Errorf("Validate: no panic for a broken pattern")
+      } // END if
+    }()
+    Validate(t_Broken{})
+  }()
+
+  // the form shows the errors, the typed values and the HTML5 constraints
+  Doc := New(GC_DocTypeNONE,0x00).FormErrors(Errors,Values).FormFields("",&Signup).FormErrorsEnd().TextField("Name","","","Fr").String()
+  for _,Fragment := range []string{
+    `<input type="text" id="Name" name="Name" required="required" minlength="3" maxlength="20" class="invalid" aria-invalid="true" aria-describedby="Name-error" value="Fr" /><span class="invalid-message" id="Name-error">Please use at least 3 characters.</span>`,
+    `<input type="number" id="Age" name="Age" min="1" max="30" class="invalid" aria-invalid="true" aria-describedby="Age-error" value="42" />`,
+    `<input type="text" id="Code" name="Code" pattern="[A-Z]{2}-[0-9]+" class="invalid" aria-invalid="true" aria-describedby="Code-error" value="de-42" />`,
+    `<select id="Breed" name="Breed" class="invalid" aria-invalid="true" aria-describedby="Breed-error"><option value="PK">Pekin</option><option value="RN">Rouen</option></select><span class="invalid-message" id="Breed-error">Please select one of the options.</span>`,
+    `<input type="password" id="Password" name="Password" value="quack" />`,
+    `<span class="invalid-message" id="Repeat-error">The passwords differ.</span><input type="text" name="Name" value="Fr" />`,
+  } {
+    if !strings.Contains(Doc,Fragment) {
+      t.Errorf("FormErrors: »%s« not found in »%s«",Fragment,Doc)
+    } // END if
+  } // END for
+  Doc = New(GC_DocTypeNONE,0x00).FormErrors(T_FieldErrors{"Name": "Taken"},nil).TextField("Name","","","Fred","class","wide").String()
+  if Doc != `<input type="text" name="Name" value="Fred" class="wide invalid" aria-invalid="true" aria-describedby="Name-error" /><span class="invalid-message" id="Name-error">Taken</span>` {
+    t.Errorf("TextField: unexpected error attributes »%s«",Doc)
+  } // END if
+} // END Test_Validate
+
+/* */
+
+// *****************************************************
+// Testing the CSRF token of POST forms and the check of the requests
+// *****************************************************
+func Test_CSRF(t *testing.T) {
+  SetCSRF(T_CSRF{Secret: []byte("0123456789abcdef0123456789abcdef"), Origins: []string{"https://shop.example.org"},
+                 Session: func(r *http.Request) string { if Cookie,err := r.Cookie("session"); err == nil { return Cookie.Value }; return "" }})
+  defer SetCSRF(T_CSRF{})
+
+  Handler := CSRFProtect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    New(GC_DocTypeNONE,0x00).WithContext(r.Context()).FormOpen("/order","post").TagCloseAll().Write(w)
+  }))
+  Serve := func(p_Method, p_Body string, p_Headers ...string) *httptest.ResponseRecorder {
+    Request := httptest.NewRequest(p_Method,"http://example.org/order",strings.NewReader(p_Body))
+    Request.Header.Set("Content-Type","application/x-www-form-urlencoded")
+    Request.AddCookie(&http.Cookie{Name: "session", Value: "duck-42"})
+    for index := 0; index+1 < len(p_Headers); index += 2 {
+      Request.Header.Set(p_Headers[index],p_Headers[index+1])
+    } // END for
+    Recorder := httptest.NewRecorder()
+    Handler.ServeHTTP(Recorder,Request)
+    return Recorder
+  } // END Serve
+
+  // the form of a GET request carries the token of the session
+  Page := Serve("GET","").Body.String()
+  Token := CSRFToken("duck-42")
+  if Page != `<form action="/order" method="post"><input type="hidden" name="csrf_token" value="`+Token+`" /></form>` {
+    t.Errorf("CSRF: unexpected form »%s«",Page)
+  } // END if
+  if Doc := New(GC_DocTypeNONE,0x00).FormOpen("/ducks","get").String(); strings.Contains(Doc,GC_CSRFField) {
+    t.Errorf("CSRF: token in GET form »%s«",Doc)
+  } // END if
+  if Doc := New(GC_DocTypeNONE,0x00).CSRF("duck-7").FormOpen("/order","POST").String(); !strings.Contains(Doc,CSRFToken("duck-7")) {
+    t.Errorf("CSRF: token of the session missing »%s«",Doc)
+  } // END if
+  if Doc := New(GC_DocTypeNONE,0x00).FormOpen("/order","post"); strings.Contains(Doc.String(),GC_CSRFField) || Doc.Err() != v_CSRFSession {
+    t.Errorf("CSRF: expected no token and an error without session, got »%s« %v",Doc.String(),Doc.Err())
+  } // END if
+  func() {
+    defer func() {
+      if Error := recover(); Error == nil {
+        t.Errorf("CSRF: expected a panic for a secret without Session function")
+      } // END if
+    }()
+    SetCSRF(T_CSRF{Secret: []byte("0123456789abcdef0123456789abcdef")})
+  }()
+
+  Body := url.Values{GC_CSRFField: {Token}, "Quantity": {"3"}}.Encode()
+  for _,Test := range []struct{ Body string; Headers []string; Status int }{
+    {Body, []string{"Origin","http://example.org","Sec-Fetch-Site","same-origin"}, http.StatusOK},
+    {Body, nil, http.StatusOK},
+    {"Quantity=3", []string{GC_CSRFHeader,Token}, http.StatusOK},
+    {Body, []string{"Origin","https://shop.example.org","Sec-Fetch-Site","cross-site"}, http.StatusOK},
+    {"Quantity=3", nil, http.StatusForbidden},
+    {Body, []string{"Sec-Fetch-Site","cross-site"}, http.StatusForbidden},
+    {Body, []string{"Origin","https://evil.example.com"}, http.StatusForbidden},
+    {url.Values{GC_CSRFField: {CSRFToken("duck-7")}}.Encode(), nil, http.StatusForbidden},
+    {url.Values{GC_CSRFField: {Token[:len(Token)-2]}}.Encode(), nil, http.StatusForbidden},
+  } {
+    if Recorder := Serve("POST",Test.Body,Test.Headers...); Recorder.Code != Test.Status {
+      t.Errorf("CSRF: %s %v: expected %d got %d %s",Test.Body,Test.Headers,Test.Status,Recorder.Code,Recorder.Body.String())
+    } // END if
+  } // END for
+
+  // multipart bodies are not parsed, the token is read from the first fields
+  Multipart := func(p_Token string) *http.Request {
+    var Body bytes.Buffer
+    Writer := multipart.NewWriter(&Body)
+    if p_Token != "" {
+      Writer.WriteField(GC_CSRFField,p_Token)
+    } // END if
+    Part,_ := Writer.CreateFormFile("Photo","duck.bin")
+    Part.Write(make([]byte,64<<10))
+    Writer.Close()
+    Request := httptest.NewRequest("POST","http://example.org/photos",&Body)
+    Request.Header.Set("Content-Type",Writer.FormDataContentType())
+    Request.AddCookie(&http.Cookie{Name: "session", Value: "duck-42"})
+    return Request
+  } // END Multipart
+  Length := Multipart(Token).ContentLength
+  Upload := CSRFProtect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    Body,_ := io.ReadAll(r.Body)
+    fmt.Fprintf(w,"%t %d",r.MultipartForm == nil,len(Body))
+  }))
+  for _,Test := range []struct{ Token string; Status int; Body string }{
+    {Token, http.StatusOK, fmt.Sprintf("true %d",Length)},
+    {"", http.StatusForbidden, "CSRF token missing\n"},
+    {CSRFToken("duck-7"), http.StatusForbidden, "CSRF token invalid\n"},
+  } {
+    Recorder := httptest.NewRecorder()
+    Upload.ServeHTTP(Recorder,Multipart(Test.Token))
+    if Recorder.Code != Test.Status || Recorder.Body.String() != Test.Body {
+      t.Errorf("CSRF: multipart %q: expected %d »%s« got %d »%s«",Test.Token,Test.Status,Test.Body,Recorder.Code,Recorder.Body.String())
+    } // END if
+  } // END for
+
+  // tokens expire
+  Config := v_CSRF.Load()
+  if err := csrfVerify(Config,"duck-42",csrfToken(Config,"duck-42",time.Now().Add(-13*time.Hour)),time.Now()); err != v_CSRFExpired {
+    t.Errorf("CSRF: expected an expired token, got %v",err)
+  } // END if
+} // END Test_CSRF
+
+/* */
+
+// *****************************************************
+// Testing the form controls in method and string form
+// *****************************************************
+func Test_FormControls(t *testing.T) {
+  Breeds := map[string]string{"Pekin": "PK", "Rouen": "RN"}
+  Doc := New(GC_DocTypeNONE,0x00).
+           FieldsetOpen("Order","class","order").
+             Label("Qty","Quantity").NumberField("Qty","1","99","","3","id","Qty").Output("Total","Qty Price","37.50").
+             RadioGroup("Breed","choice","RN",nil,Breeds,"required").
+             TextArea("Note","3","","").
+             EmailField("Mail","30","","").DateField("Day","2025-01-01","","").
+             FileField("Photo","image/*",true).
+             ResetButton("Clear").Button("calc","Calculate","","hx-post","/calc").
+           TagCloseAll().String()
+  Expected := `<fieldset class="order"><legend>Order</legend><label for="Qty">Quantity</label><input type="number" name="Qty" min="1" max="99" value="3" id="Qty" /><output name="Total" for="Qty Price">37.50</output><label class="choice"><input type="radio" name="Breed" value="PK" required />Pekin</label><label class="choice"><input type="radio" name="Breed" value="RN" checked="checked" required />Rouen</label><textarea name="Note" rows="3"></textarea><input type="email" name="Mail" size="30" /><input type="date" name="Day" min="2025-01-01" /><input type="file" name="Photo" accept="image/*" multiple="multiple" /><button type="reset">Clear</button><button type="button" name="calc" hx-post="/calc">Calculate</button></fieldset>`
+  if Doc != Expected {
+    t.Errorf("FormControls: expected »%s« got »%s«",Expected,Doc)
+  } // END if
+
+  // the string versions
+  for _,Test := range []struct{ Got, Expected string }{
+    {TextField("Name","20","40","Fred"), `<input type="text" name="Name" size="20" maxlength="40" value="Fred" />`},
+    {TextArea("Note","","","<b>"), `<textarea name="Note"><b></textarea>`},
+    {PasswordField("Secret","","",""), `<input type="password" name="Secret" />`},
+    {RangeField("Volume","0","11","1","5"), `<input type="range" name="Volume" min="0" max="11" step="1" value="5" />`},
+    {ColorField("Color","#ff8800"), `<input type="color" name="Color" value="#ff8800" />`},
+    {InputField("week","Week","2025-W23"), `<input type="week" name="Week" value="2025-W23" />`},
+    {RadioGroup("Size","","M",nil,[]string{"S","M"}), `<label><input type="radio" name="Size" value="S" />S</label><label><input type="radio" name="Size" value="M" checked="checked" />M</label>`},
+    {DataList("breeds",nil,[]string{"Pekin","Rouen"}), `<datalist id="breeds"><option value="Pekin"></option><option value="Rouen"></option></datalist>`},
+    {Fieldset("",Label("","Free")), `<fieldset><label>Free</label></fieldset>`},
+    {ResetButton("Clear","class","secondary"), `<button type="reset" class="secondary">Clear</button>`},
+  } {
+    if Test.Got != Test.Expected {
+      t.Errorf("FormControls: expected »%s« got »%s«",Test.Expected,Test.Got)
+    } // END if
+  } // END for
+} // END Test_FormControls
+
+/* */
+
+// *****************************************************
+// Testing select menus from maps, pairs and structs
+// *****************************************************
+type t_BreedChoice struct {
+  Code  string
+  Name  string
+  Class string
+} // END t_BreedChoice
+
+func Test_Select(t *testing.T) {
+  Breeds := map[string]string{"Rouen": "RN", "Pekin": "PK", "Cayuga": "CA"}
+  Doc := New(GC_DocTypeNONE,0x00).SelectMenu("Breed","menu","item","PK",nil,Breeds,"size",3).String()
+  Expected := `<select class="menu" name="Breed" size="3"><option value="CA" class="item">Cayuga</option><option value="PK" class="item" selected="selected">Pekin</option><option value="RN" class="item">Rouen</option></select>`
+  if Doc != Expected {
+    t.Errorf("SelectMenu: expected »%s« got »%s«",Expected,Doc)
+  } // END if
+
+  Choices := []t_BreedChoice{{"RN","Rouen","Heavy"},{"CA","Cayuga","Medium"},{"PK","Pekin","Heavy"}}
+  for _,Test := range []struct{ Menu T_Select; Items any; Expected string }{
+    {T_Select{Selected: []string{"2"}}, [][2]string{{"1","One"},{"2","Two"}},
+     `<select name="Menu"><option value="1">One</option><option value="2" selected="selected">Two</option></select>`},
+    {T_Select{KeyField: "Code", LabelField: "Name", GroupField: "Class", Multiple: true, Selected: []string{"RN","PK"}, Disabled: []string{"CA"}, Compare: CmpAsc}, Choices,
+     `<select name="Menu" multiple="multiple"><optgroup label="Medium"><option value="CA" disabled="disabled">Cayuga</option></optgroup>` +
+     `<optgroup label="Heavy"><option value="PK" selected="selected">Pekin</option><option value="RN" selected="selected">Rouen</option></optgroup></select>`},
+    {T_Select{Placeholder: "None"}, []T_Option{{Value: "x", Label: "Extra", Group: "More"},{Value: "s", Label: "Standard"}},
+     `<select name="Menu"><option value="">None</option><option value="s">Standard</option><optgroup label="More"><option value="x">Extra</option></optgroup></select>`},
+    {T_Select{}, []int{3,1}, `<select name="Menu"><option value="3">3</option><option value="1">1</option></select>`},
+  } {
+    if Doc := Select("Menu",Test.Menu,Test.Items); Doc != Test.Expected {
+      t.Errorf("Select: expected »%s« got »%s«",Test.Expected,Doc)
+    } // END if
+  } // END for
+
+  // the value the user chose is kept, the message follows the closed menu
+  Doc = New(GC_DocTypeNONE,0x00).FormErrors(T_FieldErrors{"Breed": "Sold out"},url.Values{"Breed": {"RN"}}).
+          Select("Breed",T_Select{Selected: []string{"PK"}},[]string{"PK","RN"}).String()
+  Expected = `<select name="Breed" class="invalid" aria-invalid="true" aria-describedby="Breed-error"><option value="PK">PK</option><option value="RN" selected="selected">RN</option></select><span class="invalid-message" id="Breed-error">Sold out</span>`
+  if Doc != Expected {
+    t.Errorf("Select: expected »%s« got »%s«",Expected,Doc)
+  } // END if
+} // END Test_Select
+
+/* */
+
+// *****************************************************
+// Testing uploads: the form with the file input, reading and saving the files
+// *****************************************************
+func Test_Upload(t *testing.T) {
+  Upload := T_Upload{MaxSize: 1024, Types: []string{"image/*","text/plain"}}
+  Doc := New(GC_DocTypeNONE,0x00).FormOpen("/photos","post").TextField("Title","","","").UploadField("Photo",Upload,true).TagCloseAll().String()
+  if Doc != `<form enctype="multipart/form-data" action="/photos" method="post"><input type="text" name="Title" /><input type="file" name="Photo" accept="image/*,text/plain" multiple="multiple" /></form>` {
+    t.Errorf("Upload: unexpected form »%s«",Doc)
+  } // END if
+  Doc = New(GC_DocTypeNONE,0x00).FormOpen("/search","post").TagCloseTop().TagOpen("form","action","/photos").FileField("Photo","",false).TagCloseAll().String()
+  if Doc != `<form action="/search" method="post"></form><form action="/photos"><input type="file" name="Photo" /></form>` {
+    t.Errorf("Upload: the closed form was changed »%s«",Doc)
+  } // END if
+
+  // a request with a title, a PNG image, a file that is too large and a PDF
+  PNG := append([]byte("\x89PNG\r\n\x1a\n"),make([]byte,100)...)
+  Request := func() *http.Request {
+    var Body bytes.Buffer
+    Writer := multipart.NewWriter(&Body)
+    Writer.WriteField("Title","Ducks")
+    for _,File := range []struct{ Name string; Content []byte }{
+      {`C:\Photos\duck.png`, PNG},
+      {"big.txt", bytes.Repeat([]byte("quack "),200)},
+      {"order.pdf", []byte("%PDF-1.7 ...")},
+    } {
+      Part,_ := Writer.CreateFormFile("Photo",File.Name)
+      Part.Write(File.Content)
+    } // END for
+    Writer.Close()
+    Request := httptest.NewRequest("POST","/photos?album=7",&Body)
+    Request.Header.Set("Content-Type",Writer.FormDataContentType())
+    return Request
+  } // END Request
+
+  Expected := T_FieldErrors{"Photo": "The file big.txt is larger than 1 KB."}
+  Files,Errors := ReadUploads(Request(),Upload)
+  if len(Files) != 1 || Files[0].Name != "duck.png" || Files[0].Size != 108 || Files[0].Type != "image/png" || Files[0].Field != "Photo" {
+    t.Errorf("ReadUploads: unexpected files %+v",Files)
+  } // END if
+  if Errors.Error() != Expected.Error() {
+    t.Errorf("ReadUploads: unexpected errors »%v«",Errors)
+  } // END if
+  if _,Errors := ReadUploads(Request(),T_Upload{MaxBody: 512}); Errors[""] != "The upload is larger than 512 bytes." {
+    t.Errorf("ReadUploads: unexpected errors of a large body »%v«",Errors)
+  } // END if
+
+  // streamed into a directory, the other fields are still available
+  Dir := t.TempDir()
+  Req := Request()
+  Files,Errors = SaveUploads(Req,T_Upload{MaxSize: 1024, Types: []string{"image/png","application/pdf"}},Dir)
+  if Errors.Error() != "Photo: Files of the type text/plain; charset=utf-8 are not accepted." {
+    t.Errorf("SaveUploads: unexpected errors »%v«",Errors)
+  } // END if
+  if len(Files) != 2 || Files[0].Type != "image/png" || Files[1].Name != "order.pdf" || Files[1].Size != 12 {
+    t.Errorf("SaveUploads: unexpected files %+v",Files)
+  } else if Content,err := os.ReadFile(Files[0].Path); err != nil || !bytes.Equal(Content,PNG) || !strings.HasSuffix(Files[0].Path,".png") {
+    t.Errorf("SaveUploads: unexpected file %s, %v",Files[0].Path,err)
+  } // END if
+  if Parameter := ReadReqParameter(Req); Parameter["Title"] != "Ducks" || Parameter["album"] != "7" {
+    t.Errorf("SaveUploads: form values missing %v",Parameter)
+  } // END if
+
+  // behind CSRFProtect the limits apply, a parsed body is refused
+  SetCSRF(T_CSRF{Secret: []byte("0123456789abcdef0123456789abcdef"), Session: func(r *http.Request) string { return "duck-42" }})
+  defer SetCSRF(T_CSRF{})
+  Protected := func(p_Handler func(r *http.Request) string) string {
+    var Body bytes.Buffer
+    Writer := multipart.NewWriter(&Body)
+    Writer.WriteField(GC_CSRFField,CSRFToken("duck-42"))
+    Part,_ := Writer.CreateFormFile("Photo","duck.png")
+    Part.Write(append(PNG,make([]byte,5<<10)...))
+    Writer.Close()
+    Request := httptest.NewRequest("POST","/photos",&Body)
+    Request.Header.Set("Content-Type",Writer.FormDataContentType())
+    Recorder := httptest.NewRecorder()
+    CSRFProtect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      w.Write([]byte(p_Handler(r)))
+    })).ServeHTTP(Recorder,Request)
+    return Recorder.Body.String()
+  } // END Protected
+  for _,Test := range []struct{ Name string; Handler func(r *http.Request) string; Expected string }{
+    {"SaveUploads", func(r *http.Request) string {
+      Files,Errors := SaveUploads(r,T_Upload{Types: []string{"image/png"}},Dir)
+      return fmt.Sprintf("%d %t %d",len(Files),r.FormValue(GC_CSRFField) != "",len(Errors))
+    }, "1 true 0"},
+    {"ReadUploads", func(r *http.Request) string {
+      _,Errors := ReadUploads(r,T_Upload{MaxBody: 100})
+      return Errors.Error()
+    }, ": The upload is larger than 100 bytes."},
+    {"parsed", func(r *http.Request) string {
+      r.FormValue("Title")
+      _,Errors := ReadUploads(r,T_Upload{MaxBody: 100})
+      _,Saved := SaveUploads(r,T_Upload{},Dir)
+      return fmt.Sprint(Errors[""] == v_UploadParsed.Error() && Saved[""] == v_UploadParsed.Error())
+    }, "true"},
+  } {
+    if Result := Protected(Test.Handler); Result != Test.Expected {
+      t.Errorf("Upload behind CSRFProtect %s: expected »%s« got »%s«",Test.Name,Test.Expected,Result)
+    } // END if
+  } // END for
+} // END Test_Upload
+
+/* */
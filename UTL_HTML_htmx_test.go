@@ -0,0 +1,88 @@
+package UTL_HTML
+// -------------------------------------------------------------------------------------------------
+
+//
+// -------------------------------------------------------------------------------------------------
+
+import (
+  "strings"
+  "testing"
+  "net/http/httptest"
+)
+
+/* */
+
+// *****************************************************
+// Testing full page vs. fragment for htmx-requests
+// *****************************************************
+func buildHxPage() *T_HTML {
+  return New(GC_DocTypeHTML5,0x00).
+         HtmlOpen().
+           BodyOpen().
+             Header("1","Cities").
+             Tag("input","",Attributes([]string{"type","search","name","q"},HxGet("/cities"),HxTarget("#result"),HxSwap(GC_HxSwapInnerHTML))...).
+             RegionOpen("result").
+               TableOpen().
+                 TbodyOpen().
+                   TrTdSlice("","",v_CityTable).
+               TagCloseUntil("table").
+             TagCloseTop(). // region
+             P("Footer")
+} // END buildHxPage
+
+func Test_WriteHx(t *testing.T) {
+  // normal request → full page
+  Request := httptest.NewRequest("GET","/cities",nil)
+  Recorder := httptest.NewRecorder()
+  buildHxPage().WriteHx(Recorder,Request,"")
+  Body := Recorder.Body.String()
+  if !strings.HasPrefix(Body,"<!DOCTYPE html>") || !strings.Contains(Body,`<div id="result"><table>`) || !strings.HasSuffix(Body,"<p>Footer</p></body></html>") {
+    t.Errorf("WriteHx: full page expected »%s«",Body)
+  } // END if
+  if !strings.Contains(Body,`hx-get="/cities" hx-target="#result" hx-swap="innerHTML"`) {
+    t.Errorf("WriteHx: hx-attributes missing »%s«",Body)
+  } // END if
+
+  // htmx request → content of the region only
+  Request.Header.Set("HX-Request","true")
+  Request.Header.Set("HX-Target","result")
+  Recorder = httptest.NewRecorder()
+  SetHxTrigger(Recorder,"citiesLoaded")
+  buildHxPage().WriteHx(Recorder,Request,"")
+  Body = Recorder.Body.String()
+  if !strings.HasPrefix(Body,"<table><tbody><tr>") || !strings.HasSuffix(Body,"</tbody></table>") {
+    t.Errorf("WriteHx: fragment expected »%s«",Body)
+  } // END if
+  if Recorder.Header().Get("HX-Trigger") != "citiesLoaded" || Recorder.Header().Get("Vary") != "HX-Request" {
+    t.Errorf("WriteHx: unexpected response headers %v",Recorder.Header())
+  } // END if
+} // END Test_WriteHx
+
+/* */
+
+// *****************************************************
+// Testing regions together with progressive flushing
+// *****************************************************
+func Test_RegionFlush(t *testing.T) {
+  Recorder := httptest.NewRecorder()
+  v_Doc := New(GC_DocTypeNONE,0x00).FlushEvery(Recorder,2,0).
+           Tag("h1","Ducks").
+           Flush().
+           RegionOpen("result").
+             TableOpen().
+               TrTdSlice("","",[][]int{{1},{2},{3},{4},{5}})
+  if Recorder.Body.String() != "<h1>Ducks</h1>" {
+    t.Errorf("RegionFlush: the open region was flushed »%s«",Recorder.Body.String())
+  } // END if
+  v_Doc.TagCloseAll().Tag("p","after the region")
+  Expected := `<table><tr><td class="">1</td></tr><tr><td class="">2</td></tr><tr><td class="">3</td></tr><tr><td class="">4</td></tr><tr><td class="">5</td></tr></table>`
+  if Region := v_Doc.Region("result"); Region != Expected {
+    t.Errorf("RegionFlush: expected »%s« got »%s«",Expected,Region)
+  } // END if
+  v_Doc.Flush()
+  if Region := v_Doc.Tag("p","more content than the region had").Region("result"); Region != "" {
+    t.Errorf("RegionFlush: expected nothing for a flushed region, got »%s«",Region)
+  } // END if
+} // END Test_RegionFlush
+
+/* */
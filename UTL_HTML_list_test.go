@@ -0,0 +1,92 @@
+package UTL_HTML
+// -------------------------------------------------------------------------------------------------
+
+//
+// -------------------------------------------------------------------------------------------------
+
+import (
+  "fmt"
+  "strings"
+  "testing"
+)
+
+/* */
+
+// *****************************************************
+// Testing lists from slices, maps, structs and nested data
+// *****************************************************
+type t_ListPond struct {
+  Name    string
+  Ducks   []string
+  Depth   float64         `html:"Format='%.1f m';ColHeader='Depth'"`
+  Secret  string          `html:"Skip"`
+  Address struct{ City, Country string }
+  Feeding map[string]int
+}
+
+func Test_List(t *testing.T) {
+  for _,Test := range []struct{ Name, Result, Expected string }{
+    {"UlSlice", New(GC_DocTypeNONE,0x00).UlSlice("ducks","duck",[]string{"Pekin","Rouen",""}).String(),
+      `<ul class="ducks"><li class="duck">Pekin</li><li class="duck">Rouen</li><li class="duck"></li></ul>`},
+    {"OlSlice", New(GC_DocTypeNONE,0x00).OlSlice("","",[]*int{new(int),nil}).String(),
+      `<ol><li>0</li><li>&nbsp;</li></ol>`},
+    {"DlMap", New(GC_DocTypeNONE,0x00).DlMap("","",CmpDesc,map[string]int{"b": 2, "a": 1, "c": 3}).String(),
+      `<dl><dt>c</dt><dd>3</dd><dt>b</dt><dd>2</dd><dt>a</dt><dd>1</dd></dl>`},
+    {"nested", List(T_List{ListClass: []string{"top","sub"}, ItemClass: []string{"","item"}},map[string]any{"colors": []any{"white",[]int{1,2}}, "size": 3}),
+      `<dl class="top"><dt>colors</dt><dd><ul class="sub"><li class="item">white</li><li class="item"><ul class="sub"><li class="item">1</li><li class="item">2</li></ul></li></ul></dd><dt>size</dt><dd>3</dd></dl>`},
+    {"struct", List(T_List{Ordered: true},&t_ListPond{Name: "Mill pond", Ducks: []string{"Pekin"}, Depth: 2.25, Address: struct{ City, Country string }{"Leipzig","DE"}, Feeding: map[string]int{"bread": 0}}),
+      `<dl><dt>Name</dt><dd>Mill pond</dd><dt>Ducks</dt><dd><ol><li>Pekin</li></ol></dd><dt>Depth</dt><dd>2.2 m</dd>` +
+      `<dt>Address</dt><dd><dl><dt>City</dt><dd>Leipzig</dd><dt>Country</dt><dd>DE</dd></dl></dd><dt>Feeding</dt><dd><dl><dt>bread</dt><dd>0</dd></dl></dd></dl>`},
+    {"Render", List(T_List{Render: func(p_Item T_ListItem) string {
+        if p_Item.Depth == 0 { return "" } // the default formatting
+        return fmt.Sprintf("%d.%d %v",p_Item.Depth,p_Item.Index,p_Item.Value)
+      }},[][]string{{"a","b"}}),
+      `<ul><li><ul><li>1.0 a</li><li>1.1 b</li></ul></li></ul>`},
+    {"MaxDepth", List(T_List{MaxDepth: 1},[]any{1,[]int{2,3}}),
+      `<ul><li>1</li><li>[2 3]</li></ul>`},
+  } {
+    if Test.Result != Test.Expected {
+      t.Errorf("List %s:\n got      %s\n expected %s",Test.Name,Test.Result,Test.Expected)
+    } // END if
+  } // END for
+
+  defer func() {
+    if Error := recover(); Error == nil || !strings.Contains(fmt.Sprint(Error),"Unknown datatype used in List: int") {
+      t.Errorf("List: expected a panic for a single value, got %v",Error)
+    } // END if
+  }()
+  List(T_List{},42)
+} // END Test_List
+
+/* */
+
+// *****************************************************
+// Testing the tree view of nested data
+// *****************************************************
+func Test_Tree(t *testing.T) {
+  Config := map[string]any{"ponds": []any{"Mill pond",map[string]int{"depth": 2}}, "open": true, "feeding": []string{}}
+  for _,Test := range []struct{ Name, Result, Expected string }{
+    {"collapsed", Tree(T_Tree{TreeClass: "tree", LeafClass: "leaf", CountClass: "count"},Config),
+      `<ul class="tree"><li class="leaf">feeding</li><li class="leaf">open: true</li>` +
+      `<li><details><summary>ponds <span class="count">2</span></summary><ul><li class="leaf">Mill pond</li>` +
+      `<li><details><summary>1 <span class="count">1</span></summary><ul><li class="leaf">depth: 2</li></ul></details></li></ul></details></li></ul>`},
+    {"Open", Tree(T_Tree{Open: 1, NoCount: true, NodeClass: "node", Compare: CmpDesc},map[string][]int{"a": {1}, "b": {2}}),
+      `<ul><li><details class="node" open="open"><summary>b</summary><ul><li>2</li></ul></details></li>` +
+      `<li><details class="node" open="open"><summary>a</summary><ul><li>1</li></ul></details></li></ul>`},
+    {"struct", Tree(T_Tree{Open: -1, NoCount: true},t_ListPond{Name: "Mill pond", Depth: 2, Address: struct{ City, Country string }{"Leipzig","DE"}}),
+      `<ul><li>Name: Mill pond</li><li>Ducks</li><li>Depth: 2.0 m</li>` +
+      `<li><details open="open"><summary>Address</summary><ul><li>City: Leipzig</li><li>Country: DE</li></ul></details></li>` +
+      `<li>Feeding</li></ul>`},
+    {"Label", Tree(T_Tree{Label: func(p_Node T_TreeNode) string {
+        if p_Node.Leaf { return "" } // the default label
+        return fmt.Sprintf("%s (%d/%d)",p_Node.Key,p_Node.Depth,p_Node.Children)
+      }},map[string]map[string]int{"pond": {"ducks": 3}}),
+      `<ul><li><details><summary>pond (0/1) <span>1</span></summary><ul><li>ducks: 3</li></ul></details></li></ul>`},
+  } {
+    if Test.Result != Test.Expected {
+      t.Errorf("Tree %s:\n got      %s\n expected %s",Test.Name,Test.Result,Test.Expected)
+    } // END if
+  } // END for
+} // END Test_Tree
+
+/* */
@@ -8,6 +8,7 @@ import (
   "os"
   "fmt"
   "time"
+  "strings"
   "testing"
 )
 
@@ -245,3 +246,50 @@ func Test_Table_MapIntSlice(t *testing.T) {
 } // END Test_Table_MapIntSlice
 
 /* */
+
+// ***********************************************
+// Testing pagination of a map[string]string
+// ***********************************************
+func Test_Table_MapPaginate(t *testing.T) {
+  Parameter := map[string]string{"page": "2", "size": "5", "filter": "all"}
+  v_Doc := New(GC_DocTypeNONE,0x00).
+           Paginate(Parameter,10,"filter").
+           TableOpen().
+             TbodyOpen().
+               TrTdMap("","",CmpAsc,Environment).
+           TagCloseTop().
+           PageNav("pagenav").
+           TagCloseAll()
+  Doc := v_Doc.String()
+  if strings.Count(Doc,"<tr") != 5 || !strings.Contains(Doc,">DISPLAY<") || strings.Contains(Doc,">ALLUSERSPROFILE<") {
+    t.Errorf("Paginate: wrong rows on page 2 »%s«",Doc)
+  } // END if
+  if !strings.Contains(Doc,`<span aria-current="page">2</span>`) || !strings.Contains(Doc,`<a href="?filter=all&amp;page=3&amp;size=5" title="page 3">3</a>`) || strings.Contains(Doc,`title="page 4"`) {
+    t.Errorf("Paginate: unexpected navigation »%s«",Doc)
+  } // END if
+} // END Test_Table_MapPaginate
+
+/* */
+
+// *****************************************
+// Testing a pivot table from map[string]map[string]int
+// *****************************************
+func Test_Table_MapPivot(t *testing.T) {
+  Sales := map[string]map[string]int{
+    "West": {"Q2": 20, "Q1": 30},
+    "East": {"Q1": 10, "Q3": 5},
+  }
+  Doc := New(GC_DocTypeNONE,0x00).
+         TableOpen().
+           Pivot("","","",T_Pivot{RowHeader: "Region", Empty: "–", RowTotals: true, ColumnTotals: true},Sales).
+         TagCloseAll().String()
+  Expected := `<table><thead><tr><th class="">Region</th><th class="" scope="col">Q1</th><th class="" scope="col">Q2</th><th class="" scope="col">Q3</th><th class="total" scope="col">Total</th></tr></thead>` +
+              `<tbody><tr><th class="" scope="row">East</th><td class="">10</td><td class="">–</td><td class="">5</td><td class="total">15</td></tr>` +
+              `<tr><th class="" scope="row">West</th><td class="">30</td><td class="">20</td><td class="">–</td><td class="total">50</td></tr></tbody>` +
+              `<tfoot><tr><th class="total" scope="row">Total</th><td class="total">40</td><td class="total">20</td><td class="total">5</td><td class="total">65</td></tr></tfoot></table>`
+  if Doc != Expected {
+    t.Errorf("Pivot: unexpected table »%s«",Doc)
+  } // END if
+} // END Test_Table_MapPivot
+
+/* */
@@ -0,0 +1,131 @@
+package UTL_HTML
+// -------------------------------------------------------------------------------------------------
+
+//
+// -------------------------------------------------------------------------------------------------
+
+import (
+  "io"
+  "os"
+  "time"
+  "slices"
+  "strings"
+  "testing"
+  "net/http"
+  "net/http/httptest"
+)
+
+/* */
+
+// *****************************************************
+// Testing the namespaces and the typed getters of the request parameters
+// *****************************************************
+func Test_ReadParams(t *testing.T) {
+  Request := httptest.NewRequest("POST","/ducks?page=2&tag=pekin&tag=rouen&since=2025-06-01",strings.NewReader("UserName=mallory&qty=three&express=on"))
+  Request.Header.Set("Content-Type","application/x-www-form-urlencoded")
+  Request.Header.Set("HX-Request","true")
+  Request.SetBasicAuth("fred","quack")
+  Request.AddCookie(&http.Cookie{Name: "session", Value: "duck-42"})
+
+  Params,err := ReadParams(Request)
+  if err != nil {
+    t.Fatalf("ReadParams: unexpected error %v",err)
+  } // END if
+  switch {
+    case Params.Query.Int("page",1) != 2 || Params.Query.Int("size",25) != 25: t.Errorf("ReadParams: unexpected page or size")
+    case !slices.Equal(Params.Query.Strings("tag"),[]string{"pekin","rouen"}): t.Errorf("ReadParams: multi-values %v",Params.Query.Strings("tag"))
+    case !Params.Query.Time("since",time.Time{}).Equal(time.Date(2025,6,1,0,0,0,0,time.Local)): t.Errorf("ReadParams: unexpected date")
+    case !Params.Header.Bool("hx-request",false) || Params.Cookie.String("session","") != "duck-42": t.Errorf("ReadParams: unexpected header or cookie")
+    case !Params.Form.Bool("express",false) || Params.Form.Has("page"): t.Errorf("ReadParams: unexpected form fields %v",Params.Form.Names())
+    case !Params.Auth.OK || Params.Auth.User != "fred" || Params.Form.String("UserName","") != "mallory": t.Errorf("ReadParams: form field mixed with credentials %+v",Params.Auth)
+  } // END switch
+  if Quantity := Params.Form.Int("qty",1); Quantity != 1 || Params.Errors().Error() != `qty: "three" is not a whole number` {
+    t.Errorf("ReadParams: expected the default and an error, got %d »%v«",Quantity,Params.Errors())
+  } // END if
+  if Tags := ParamValue(Params.Query,"tag",[]string(nil)); len(Tags) != 2 {
+    t.Errorf("ParamValue: unexpected multi-values %v",Tags)
+  } // END if
+
+  // the compatibility shim keeps its old behaviour
+  if Parameter := ReadReqParameter(Request); Parameter["UserName"] != "fred" || Parameter["UserAuth"] != "quack" || Parameter["tag"] != "pekin;rouen" || Parameter["Hx-Request"] != "true" {
+    t.Errorf("ReadReqParameter: unexpected map %v",Parameter)
+  } // END if
+
+  // a JSON body
+  Request = httptest.NewRequest("PUT","/ducks/7",strings.NewReader(`{"id": 9007199254740993, "name": "Pekin", "flying": false, "tags": ["white","heavy"], "address": {"city": "Leipzig"}}`))
+  Request.Header.Set("Content-Type","application/json; charset=utf-8")
+  Params,err = ReadParams(Request)
+  switch {
+    case err != nil: t.Errorf("ReadParams: unexpected error %v",err)
+    case Params.Form.Int64("id",0) != 9007199254740993 || Params.Form.Bool("flying",true): t.Errorf("ReadParams: unexpected JSON values %v",Params.Form.Names())
+    case Params.Form.String("address.city","") != "Leipzig" || len(Params.Form.Strings("tags")) != 2: t.Errorf("ReadParams: unexpected JSON members %v",Params.Form.Names())
+    case Params.JSON.(map[string]any)["name"] != "Pekin": t.Errorf("ReadParams: unexpected JSON %v",Params.JSON)
+  } // END switch
+  if Body,_ := io.ReadAll(Request.Body); !strings.HasPrefix(string(Body),`{"id": 9007199254740993`) {
+    t.Errorf("ReadParams: the JSON body was not restored, got »%s«",Body)
+  } // END if
+  Request = httptest.NewRequest("POST","/ducks",strings.NewReader(`{"name": `))
+  Request.Header.Set("Content-Type","application/json")
+  if _,err := ReadParams(Request); err == nil {
+    t.Errorf("ReadParams: expected an error for a broken JSON body")
+  } // END if
+
+  // the compatibility shim leaves JSON bodies alone
+  Request = httptest.NewRequest("POST","/ducks?page=3",strings.NewReader(`{"name": `))
+  Request.Header.Set("Content-Type","application/json")
+  if Parameter := ReadReqParameter(Request); Parameter["page"] != "3" || Parameter["name"] != "" {
+    t.Errorf("ReadReqParameter: unexpected map for a JSON request %v",Parameter)
+  } // END if
+  if Body,_ := io.ReadAll(Request.Body); string(Body) != `{"name": ` {
+    t.Errorf("ReadReqParameter: the JSON body was read, got »%s«",Body)
+  } // END if
+} // END Test_ReadParams
+
+/* */
+
+// *****************************************************
+// Testing Basic authentication with the user map and the htpasswd file
+// *****************************************************
+func Test_RequireAuth(t *testing.T) {
+  File := t.TempDir() + "/htpasswd"
+  if err := os.WriteFile(File,[]byte("# ducks\n"+HtpasswdLine("fred","quack")+"\n"+HtpasswdLine("daisy","s3cret")+"\n"),0600); err != nil {
+    t.Fatal(err)
+  } // END if
+  Htpasswd,err := LoadHtpasswd(File)
+  if err != nil {
+    t.Fatalf("LoadHtpasswd: unexpected error %v",err)
+  } // END if
+  os.WriteFile(File,[]byte("fred:quack\n"),0600)
+  if _,err := LoadHtpasswd(File); err == nil || !strings.HasSuffix(err.Error(),":1: not a salted SHA-256 entry") {
+    t.Errorf("LoadHtpasswd: expected an error for a plain password, got %v",err)
+  } // END if
+
+  for _,Verifier := range []T_Verifier{Htpasswd, T_UserMap{"fred": "quack", "daisy": "s3cret"}} {
+    Handler := RequireAuth("Duck Pond",Verifier,http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      New(GC_DocTypeNONE,0x00).WithContext(r.Context()).Pf("","Hello %s, %s",New(GC_DocTypeNONE,0x00).WithContext(r.Context()).User(),AuthUser(r)).Write(w)
+    }))
+    for _,Test := range []struct{ User, Password string; Status int }{
+      {"fred", "quack", http.StatusOK},
+      {"daisy", "s3cret", http.StatusOK},
+      {"fred", "quak", http.StatusUnauthorized},
+      {"donald", "", http.StatusUnauthorized},
+      {"", "", http.StatusUnauthorized},
+    } {
+      Request := httptest.NewRequest("GET","/pond",nil)
+      if Test.User != "" {
+        Request.SetBasicAuth(Test.User,Test.Password)
+      } // END if
+      Recorder := httptest.NewRecorder()
+      Handler.ServeHTTP(Recorder,Request)
+      Body := Recorder.Body.String()
+      switch {
+        case Recorder.Code != Test.Status: t.Errorf("RequireAuth %T %s: expected %d got %d",Verifier,Test.User,Test.Status,Recorder.Code)
+        case Test.Status == http.StatusOK && Body != "<p>Hello "+Test.User+", "+Test.User+"</p>": t.Errorf("RequireAuth: unexpected page »%s«",Body)
+        case Test.Status != http.StatusOK && Recorder.Header().Get("WWW-Authenticate") != `Basic realm="Duck Pond", charset="UTF-8"`: t.Errorf("RequireAuth: unexpected challenge %v",Recorder.Header())
+        case Test.Status != http.StatusOK && !strings.Contains(Body,"<h1>"+GC_AuthTitle+"</h1>"): t.Errorf("RequireAuth: unexpected 401 page »%s«",Body)
+      } // END switch
+    } // END for
+  } // END for
+} // END Test_RequireAuth
+
+/* */
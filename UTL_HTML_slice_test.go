@@ -8,6 +8,8 @@ import (
   "os"
   "fmt"
   "time"
+  "strings"
+  "reflect"
   "testing"
 )
 
@@ -102,3 +104,394 @@ func TestTable_SliceSlice(t *testing.T) {
 } // END TestTable_SliceSlice
 
 /* */
+
+// *****************************************
+// Testing sortable table headers
+// *****************************************
+func TestTable_Sortable(t *testing.T) {
+  Parameter := map[string]string{"sort": "Population", "dir": "desc", "view": "cities", "User-Agent": "test"}
+  v_Doc := New(GC_DocTypeNONE,0x00).
+           Sortable(Parameter,"view").
+           TableOpen().
+             TheadOpen().
+               TrThStruct("","","",t_CityRec{}).
+             TagCloseTop().
+             TbodyOpen().
+               TrTdSlice("","",v_CityList).
+           TagCloseAll()
+  Doc := v_Doc.String()
+  if !strings.Contains(Doc,`aria-sort="descending"><a href="?dir=asc&amp;sort=Population&amp;view=cities">Population in 2023&nbsp;▼</a>`) {
+    t.Errorf("Sortable: header of sorted column not found »%s«",Doc)
+  } // END if
+  if !strings.Contains(Doc,`<a href="?dir=asc&amp;sort=Name&amp;view=cities">City Name</a>`) {
+    t.Errorf("Sortable: header link not found »%s«",Doc)
+  } // END if
+  if Houston,Charleston := strings.Index(Doc,"Houston"),strings.Index(Doc,"Charleston"); Houston < 0 || Houston > Charleston {
+    t.Errorf("Sortable: rows not sorted descending »%s«",Doc)
+  } // END if
+  if v_CityList[0].Name != "Houston" || v_CityList[2].Name != "Charleston" {
+    t.Errorf("Sortable: the slice has been modified")
+  } // END if
+} // END TestTable_Sortable
+
+/* */
+
+// *****************************************
+// Testing value formatting with the html struct-tag
+// *****************************************
+type t_Quote struct {
+  Name    string     `html:"ColHeader='Symbol' Link='/ticker/{Name}' Order='2'"`
+  Price   float32    `html:"Format='%.2f' Align='right' Width='6em'"`
+  Date    time.Time  `html:"Layout='2006-01-02' Order='1'"`
+  Volume  *int       `html:"Empty='—' Align='right'"`
+  Dividend float64   `html:"Empty='n/a' Format='%.3f'"`
+} // END t_Quote
+
+func TestTable_StructTagFormat(t *testing.T) {
+  Volume := 46165
+  Quotes := []t_Quote{
+    {Name: "XOMO", Price: 14.4812, Date: time.Date(2025,5,2,16,0,0,0,time.UTC), Volume: &Volume, Dividend: 0.151},
+    {Name: "S&P", Price: 5667.56, Date: time.Date(2025,5,2,16,0,0,0,time.UTC)},
+  }
+  Doc := New(GC_DocTypeNONE,0x00).
+         TableOpen().
+           TheadOpen().
+             TrThStruct("","","",t_Quote{}).
+           TagCloseTop().
+           TbodyOpen().
+             TrTdSlice("","",Quotes).
+         TagCloseAll().String()
+  Expected := []string{
+    `<th class="">Date</th><th class="">Symbol</th><th class="" style="text-align: right; width: 6em;">Price</th>`,
+    `<td class="">2025-05-02</td><td class=""><a href="/ticker/XOMO">XOMO</a></td><td class="" style="text-align: right;">14.48</td><td class="" style="text-align: right;">46165</td><td class="">0.151</td>`,
+    `<a href="/ticker/S&amp;P">S&P</a>`,
+    `<td class="" style="text-align: right;">—</td><td class="">n/a</td>`,
+  }
+  for _,Fragment := range Expected {
+    if !strings.Contains(Doc,Fragment) {
+      t.Errorf("StructTagFormat: »%s« not found in »%s«",Fragment,Doc)
+    } // END if
+  } // END for
+} // END TestTable_StructTagFormat
+
+func TestTable_StructOrderTie(t *testing.T) {
+  type t_Row struct {
+    Alpha string
+    Beta  string
+    Gamma string `html:"Order='2'"`
+  } // END t_Row
+  Doc := New(GC_DocTypeNONE,0x00).TrThStruct("","","",t_Row{}).String()
+  Expected := `<th class="">Alpha</th><th class="">Gamma</th><th class="">Beta</th>`
+  switch {
+    case !strings.Contains(Doc,Expected): t.Errorf("StructOrderTie: »%s« not found in »%s«",Expected,Doc)
+  } // END switch
+} // END TestTable_StructOrderTie
+
+/* */
+
+// *****************************************
+// Testing footer aggregates from the html struct-tag
+// *****************************************
+type t_Position struct {
+  Symbol  string   `html:"Aggregate='count'"`
+  Sector  string   `html:"Aggregate='distinct'"`
+  Shares  int      `html:"Aggregate='sum' Align='right'"`
+  Price   float32  `html:"Format='%.2f' Aggregate='avg'"`
+  Value   *float64 `html:"Format='%.0f' Aggregate='max'"`
+} // END t_Position
+
+func TestTable_StructAggregate(t *testing.T) {
+  Value := 1234.5
+  Positions := []*t_Position{
+    {Symbol: "XOM", Sector: "Energy", Shares: 100, Price: 110.25, Value: &Value},
+    {Symbol: "CVX", Sector: "Energy", Shares: 50, Price: 150.5},
+    {Symbol: "AAPL", Sector: "Technology", Shares: 25, Price: 200},
+  }
+  Doc := New(GC_DocTypeNONE,0x00).
+         TableOpen().
+           TheadOpen().
+             TrThStruct("","","",t_Position{}).
+           TagCloseTop().
+           TbodyOpen().
+             TrTdSlice("","",Positions).
+           TagCloseTop().
+           TfootAggregates("","total","").
+         TagCloseAll().String()
+  Expected := `<tfoot><tr><td class="total" data-aggregate="count">3</td><td class="total" data-aggregate="distinct">2</td>` +
+              `<td class="total" style="text-align: right;" data-aggregate="sum">175</td><td class="total" data-aggregate="avg">153.58</td>` +
+              `<td class="total" data-aggregate="max">1234</td></tr></tfoot>`
+  if !strings.Contains(Doc,Expected) {
+    t.Errorf("StructAggregate: »%s« not found in »%s«",Expected,Doc)
+  } // END if
+
+  // by column name, label in the first column
+  Doc = New(GC_DocTypeNONE,0x00).Aggregate("Population","sum","Latitude","min:%.1f").
+         TableOpen().
+           TrThStruct("","","",t_CityRec{}).
+           TrTdSlice("","",v_CityList).
+           TfootAggregates("","","Total").
+         TagCloseAll().String()
+  if !strings.Contains(Doc,`<tfoot><tr><th class="" scope="row">Total</th><td class="" style="text-align: right" data-aggregate="sum">4011065</td><td class="" style="text-align: right" data-aggregate="min">29.8</td><td class="">&nbsp;</td></tr></tfoot>`) {
+    t.Errorf("StructAggregate: unexpected footer »%s«",Doc)
+  } // END if
+
+  // no numeric values: sum and count are 0, max stays empty
+  type t_Text struct {
+    Note  string   `html:"Aggregate='sum'"`
+    Qty   *int     `html:"Aggregate='count'"`
+    Value *float64 `html:"Aggregate='max'"`
+  } // END t_Text
+  Doc = New(GC_DocTypeNONE,0x00).
+         TableOpen().
+           TrThStruct("","","",t_Text{}).
+           TrTdSlice("","",[]t_Text{{Note: "n/a"},{Note: "tbd"}}).
+           TfootAggregates("","","").
+         TagCloseAll().String()
+  Expected = `<tfoot><tr><td class="" data-aggregate="sum">0</td><td class="" data-aggregate="count">0</td><td class="" data-aggregate="max">&nbsp;</td></tr></tfoot>`
+  if !strings.Contains(Doc,Expected) {
+    t.Errorf("StructAggregate: »%s« not found in »%s«",Expected,Doc)
+  } // END if
+
+  // an unknown function panics before the table is rendered
+  type t_Bad struct {
+    Amount int `html:"Aggregate='median'"`
+  } // END t_Bad
+  HTML := New(GC_DocTypeNONE,0x00).TableOpen()
+  func() {
+    defer func() {
+      if recover() == nil {
+        t.Errorf("StructAggregate: no panic for an unknown aggregate")
+      } // END if
+    }()
+    HTML.TrThStruct("","","",t_Bad{})
+  }()
+  if Doc = HTML.String(); strings.Contains(Doc,"Amount") {
+    t.Errorf("StructAggregate: header rendered before the panic »%s«",Doc)
+  } // END if
+} // END TestTable_StructAggregate
+
+/* */
+
+// *****************************************
+// Testing group-by sections of a []struct
+// *****************************************
+type t_Sale struct {
+  Region  string
+  Product string
+  Amount  int    `html:"Aggregate='sum'"`
+} // END t_Sale
+
+func TestTable_StructGroupBy(t *testing.T) {
+  Sales := []t_Sale{
+    {"West","Pears",30}, {"East","Apples",10}, {"West","Apples",20}, {"East","Apples",5}, {"East","Pears",1},
+  }
+  Doc := New(GC_DocTypeNONE,0x00).GroupBy(GC_GroupHeader,"","Region").
+         TableOpen().
+           TrThStruct("","","",t_Sale{}).
+           TrTdSlice("","",Sales).
+           TfootAggregates("","","Total").
+         TagCloseAll().String()
+  Expected := `<table><tr><th class="">Product</th><th class="">Amount</th></tr>` +
+              `<tr class="group"><th class="" colspan="2" scope="rowgroup">East</th></tr>` +
+              `<tr><td class="">Apples</td><td class="">10</td></tr><tr><td class="">Apples</td><td class="">5</td></tr><tr><td class="">Pears</td><td class="">1</td></tr>` +
+              `<tr class="subtotal"><td class="">&nbsp;</td><td class="" data-aggregate="sum">16</td></tr>` +
+              `<tr class="group"><th class="" colspan="2" scope="rowgroup">West</th></tr>` +
+              `<tr><td class="">Pears</td><td class="">30</td></tr><tr><td class="">Apples</td><td class="">20</td></tr>` +
+              `<tr class="subtotal"><td class="">&nbsp;</td><td class="" data-aggregate="sum">50</td></tr>` +
+              `<tfoot><tr><th class="" scope="row">Total</th><td class="" data-aggregate="sum">66</td></tr></tfoot></table>`
+  if Doc != Expected {
+    t.Errorf("GroupBy header: unexpected table »%s«",Doc)
+  } // END if
+
+  // two levels with rowspan
+  Doc = New(GC_DocTypeNONE,0x00).GroupBy(GC_GroupRowspan,"Σ","Region","Product").
+        TableOpen().
+          TrTdSlice("","",Sales).
+        TagCloseAll().String()
+  Expected = `<table><tr><td rowspan="5" class="">East</td><td rowspan="2" class="">Apples</td><td class="">10</td></tr><tr><td class="">5</td></tr>` +
+             `<tr class="subtotal"><th class="" scope="row">Σ</th><td class="" data-aggregate="sum">15</td></tr>` +
+             `<tr><td class="">Pears</td><td class="">1</td></tr><tr class="subtotal"><th class="" scope="row">Σ</th><td class="" data-aggregate="sum">1</td></tr>` +
+             `<tr class="subtotal"><th class="" scope="row">Σ</th><td class="">&nbsp;</td><td class="" data-aggregate="sum">16</td></tr>`
+  if !strings.HasPrefix(Doc,Expected) {
+    t.Errorf("GroupBy rowspan: unexpected table »%s«",Doc)
+  } // END if
+} // END TestTable_StructGroupBy
+
+/* */
+
+// *****************************************
+// Testing a pivot table from a []struct
+// *****************************************
+func TestTable_StructPivot(t *testing.T) {
+  Sales := []*t_Sale{
+    {"West","Pears",30}, {"East","Apples",10}, {"West","Apples",20}, {"East","Apples",5}, nil,
+  }
+  Doc := New(GC_DocTypeNONE,0x00).
+         TableOpen().
+           Pivot("","","",T_Pivot{RowField: "Product", ColumnField: "Region", ValueField: "Amount", Aggregate: "avg:%.1f", ColumnCompare: CmpDesc},Sales).
+         TagCloseAll().String()
+  Expected := `<table><thead><tr><th class="">&nbsp;</th><th class="" scope="col">West</th><th class="" scope="col">East</th></tr></thead>` +
+              `<tbody><tr><th class="" scope="row">Apples</th><td class="">20.0</td><td class="">7.5</td></tr>` +
+              `<tr><th class="" scope="row">Pears</th><td class="">30.0</td><td class="">&nbsp;</td></tr></tbody></table>`
+  if Doc != Expected {
+    t.Errorf("Pivot: unexpected table »%s«",Doc)
+  } // END if
+} // END TestTable_StructPivot
+
+/* */
+
+// *****************************************
+// Testing conditional styling of rows and cells
+// *****************************************
+type t_Balance struct {
+  Account string
+  Amount  float64 `html:"Format='%.2f' DataClass='money'"`
+} // END t_Balance
+
+func TestTable_StyleRules(t *testing.T) {
+  Balances := []t_Balance{{"Cash",120.5}, {"Loan",-80}, {"Stock",0}}
+  Doc := New(GC_DocTypeNONE,0x00).
+         StyleRows(RuleZebra("even","odd"),func(p_Row T_RowData) T_Style {
+           if p_Row.Value("Account") == "Stock" {
+             return T_Style{Style: "font-style: italic", Attributes: []string{"title","no value"}}
+           } // END if
+           return T_Style{}
+         }).
+         StyleCells(RuleNegative("negative")).
+         StyleRowHeader().
+         TableOpen().
+           TrThStruct("","","",t_Balance{}).
+           TrTdSlice("row","cell",Balances).
+         TagCloseAll().String()
+  Expected := []string{
+    `<tr class="row even"><th class="cell" scope="row">Cash</th><td class="cell money">120.50</td></tr>`,
+    `<tr class="row odd"><th class="cell" scope="row">Loan</th><td class="cell money negative">-80.00</td></tr>`,
+    `<tr class="row even" style="font-style: italic" title="no value"><th class="cell" scope="row">Stock</th><td class="cell money">0.00</td></tr>`,
+  }
+  for _,Fragment := range Expected {
+    if !strings.Contains(Doc,Fragment) {
+      t.Errorf("StyleRules: »%s« not found in »%s«",Fragment,Doc)
+    } // END if
+  } // END for
+
+  // the rules stay active for maps until StyleEnd
+  v_Doc := New(GC_DocTypeNONE,0x00).StyleCells(RuleNegative("negative"))
+  Doc = v_Doc.TrTdMap("","",CmpAsc,map[string]int{"a": -1, "b": 2}).String()
+  if Doc != `<tr><td class="">a</td><td class="negative">-1</td></tr><tr><td class="">b</td><td class="">2</td></tr>` {
+    t.Errorf("StyleRules: unexpected map rows »%s«",Doc)
+  } // END if
+  if Doc = v_Doc.StyleEnd().TrTd("","",-3).String(); !strings.HasSuffix(Doc,`<tr><td class="">-3</td></tr>`) {
+    t.Errorf("StyleEnd: rules still active »%s«",Doc)
+  } // END if
+} // END TestTable_StyleRules
+
+/* */
+
+// *****************************************
+// Benchmarks: table from a []struct with 50k rows
+// *****************************************
+func benchCityRows(p_Rows int) []t_CityRec {
+  Result := make([]t_CityRec,p_Rows)
+  for index := range Result {
+    Result[index] = *v_CityList[index%len(v_CityList)]
+  } // END for
+  return Result
+} // END benchCityRows
+
+func BenchmarkTrTdSliceStruct(b *testing.B) {
+  Rows := benchCityRows(50000)
+  b.ResetTimer()
+  for i := 0; i < b.N; i++ {
+    New(GC_DocTypeNONE,0x00).TableOpen().TrTdSlice("class4tr","class4td",Rows).TagCloseAll()
+  } // END for
+} // END BenchmarkTrTdSliceStruct
+
+func BenchmarkStructMetaCached(b *testing.B) {
+  Type := reflect.TypeOf(t_CityRec{})
+  for i := 0; i < b.N; i++ {
+    structMeta(Type)
+  } // END for
+} // END BenchmarkStructMetaCached
+
+func BenchmarkStructMetaUncached(b *testing.B) {
+  Type := reflect.TypeOf(t_CityRec{})
+  for i := 0; i < b.N; i++ {
+    buildStructMeta(Type)
+  } // END for
+} // END BenchmarkStructMetaUncached
+
+/* */
+
+// *****************************************
+// Testing embedded and nested structs
+// *****************************************
+type t_Audit struct {
+  ID      int
+  Comment string
+} // END t_Audit
+type t_Address struct {
+  Street string
+  City   string `html:"ColHeader='Town'"`
+} // END t_Address
+type t_Customer struct {
+  t_Audit
+  *t_CityRec `html:"Skip"`
+  Name     string
+  Comment  string     // shadows t_Audit.Comment
+  Address  t_Address  `html:"ColHeader='Postal Address'"`
+  Billing  t_Address  `html:"Nested='string'"`
+} // END t_Customer
+
+func TestTable_StructNested(t *testing.T) {
+  Customers := []t_Customer{
+    {t_Audit: t_Audit{ID: 1, Comment: "hidden"}, Name: "ACME", Comment: "shown", Address: t_Address{"Desert Road 1","Phoenix"}, Billing: t_Address{"PO Box 7","Houston"}},
+  }
+  Doc := New(GC_DocTypeNONE,0x00).
+         Sortable(map[string]string{"sort": "Address.City"}).
+         TableOpen().
+           TheadOpen().
+             TrThStruct("","","",t_Customer{}).
+           TagCloseTop().
+           TbodyOpen().
+             TrTdSlice("","",Customers).
+         TagCloseAll().String()
+  Expected := []string{
+    `<th class="" rowspan="2"><a href="?dir=asc&amp;sort=ID">ID</a></th>`,
+    `<th class="" colspan="2" scope="colgroup">Postal Address</th>`,
+    `<th class="" aria-sort="ascending"><a href="?dir=desc&amp;sort=Address.City">Town&nbsp;▲</a></th>`,
+    `<td class="">1</td><td class="">ACME</td><td class="">shown</td><td class="">Desert Road 1</td><td class="">Phoenix</td><td class="">{PO Box 7 Houston}</td>`,
+  }
+  for _,Fragment := range Expected {
+    if !strings.Contains(Doc,Fragment) {
+      t.Errorf("StructNested: »%s« not found in »%s«",Fragment,Doc)
+    } // END if
+  } // END for
+} // END TestTable_StructNested
+
+/* */
+
+// *****************************************
+// Testing a struct with a pointer to its own type
+// *****************************************
+type t_Dir struct {
+  Name   string
+  Parent *t_Dir
+  Root   *t_Dir `html:"Nested='group'"`
+} // END t_Dir
+
+func TestTable_StructSelfPointer(t *testing.T) {
+  Root := &t_Dir{Name: "/"}
+  Dirs := []t_Dir{{Name: "usr", Parent: Root}}
+  if Columns := structMeta(reflect.TypeOf(t_Dir{})).Columns; len(Columns) != 3 || Columns[1].Name != "Parent" || Columns[2].Group != "" {
+    t.Errorf("StructSelfPointer: expected the columns Name, Parent and Root, got %+v",Columns)
+  } // END if
+  Doc := New(GC_DocTypeNONE,0x00).TrThStruct("","","",t_Dir{}).TrTdSlice("","",Dirs).String()
+  if !strings.Contains(Doc,`<tr><td class="">usr</td><td class="">{/ <nil> <nil>}</td><td class="">&nbsp;</td></tr>`) {
+    t.Errorf("StructSelfPointer: unexpected table »%s«",Doc)
+  } // END if
+  if Doc := Tree(T_Tree{NoCount: true},t_Dir{Name: "root"}); Doc != `<ul><li>Name: root</li><li>Parent: &nbsp;</li><li>Root: &nbsp;</li></ul>` {
+    t.Errorf("StructSelfPointer: unexpected tree »%s«",Doc)
+  } // END if
+} // END TestTable_StructSelfPointer
+
+/* */
@@ -8,7 +8,9 @@ package UTL_HTML
 import (
   "os"
   "fmt"
+  "context"
   "time"
+  "strings"
   "testing"
 	"database/sql"
 	_ "modernc.org/sqlite"
@@ -66,3 +68,442 @@ func TestTable_Sqlite3DuckBreeds(t *testing.T) {
 } // END TestTable_Sqlite3DuckBreeds
 
 /* */
+
+// ********************************************
+// Testing sortable table from a database query
+// ********************************************
+func TestTable_Sqlite3OrderBy(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	v_Doc := New(GC_DocTypeNONE,0x00).Sortable(map[string]string{"sort": "Breed", "dir": "desc"})
+	Query := v_Doc.OrderBy("SELECT Breed, Class FROM DuckBreeds","Breed","Class")
+	if Query != "SELECT * FROM (SELECT Breed, Class FROM DuckBreeds) t_sorted ORDER BY Breed DESC" {
+    t.Errorf("OrderBy: unexpected query »%s«",Query)
+	} // END if
+	if v_Doc.OrderBy("SELECT 1","Class") != "SELECT 1" || v_Doc.SortOrder("Class","Class") != "Class" {
+    t.Errorf("OrderBy: column not in whitelist accepted")
+	} // END if
+	if Limited := v_Doc.Paginate(map[string]string{"page": "2"},10).LimitQuery("SELECT * FROM DuckBreeds",v_Doc.SortOrder("Class","Breed","Class")); Limited != "SELECT * FROM (SELECT * FROM DuckBreeds) t_page ORDER BY Breed DESC LIMIT 10 OFFSET 10" {
+    t.Errorf("OrderBy: unexpected page query »%s«",Limited)
+	} // END if
+	v_Doc.PaginateEnd()
+
+	Rows,err := dbh.Query(Query)
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+  defer Rows.Close()
+
+	Doc := v_Doc.TableOpen().TheadOpen().TrThSqlRows("","",Rows).TagCloseTop().TbodyOpen().TrTdSqlRows("","",Rows).TagCloseAll().String()
+	if Ancona,Aylesbury := strings.Index(Doc,">Ancona<"),strings.Index(Doc,">Aylesbury<"); Ancona < Aylesbury {
+    t.Errorf("OrderBy: rows not sorted descending »%s«",Doc)
+	} // END if
+} // END TestTable_Sqlite3OrderBy
+
+/* */
+
+// ********************************************
+// Testing pagination of a database query
+// ********************************************
+func TestTable_Sqlite3Paginate(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	// LIMIT/OFFSET
+	Query := "SELECT Breed, Class FROM DuckBreeds"
+	v_Doc := New(GC_DocTypeNONE,0x00).Paginate(map[string]string{"page": "3"},10)
+	var Total int
+	if err := dbh.QueryRow(CountQuery(Query)).Scan(&Total); err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Rows,err := dbh.Query(v_Doc.SetPageTotal(Total).LimitQuery(Query,"Breed"))
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc := v_Doc.TableOpen().TrTdSqlRows("","",Rows).TagCloseTop().PageNav("").String()
+	Rows.Close()
+	if strings.Count(Doc,"<tr") != 8 || !strings.Contains(Doc,`<span aria-current="page">3</span>`) || !strings.Contains(Doc,`<span aria-disabled="true">›</span>`) {
+    t.Errorf("LimitQuery: unexpected last page »%s«",Doc)
+	} // END if
+
+	// keyset
+	v_Doc = New(GC_DocTypeNONE,0x00).Paginate(map[string]string{"after": "Cayuga", "size": "3"},10)
+	KeysetQuery,Args := v_Doc.KeysetQuery(Query,"Breed")
+	if Rows,err = dbh.Query(KeysetQuery,Args...); err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc = v_Doc.TableOpen().TrTdSqlRows("","",Rows).TagCloseTop().PageNav("").String()
+	Rows.Close()
+	if strings.Count(Doc,"<tr") != 3 || strings.Contains(Doc,">Cayuga<") || !strings.Contains(Doc,`title="next page"`) {
+    t.Errorf("KeysetQuery: unexpected page »%s«",Doc)
+	} // END if
+
+	// a computed numeric key is bound as number on the next page
+	Query = "SELECT Breed, ROW_NUMBER() OVER (ORDER BY Breed) AS Nr FROM DuckBreeds"
+	Parameter := map[string]string{"size": "3"}
+	for Page := 1; Page <= 2; Page++ {
+		v_Doc = New(GC_DocTypeNONE,0x00).Paginate(Parameter,10)
+		KeysetQuery,Args = v_Doc.KeysetQuery(Query,"Nr")
+		if Rows,err = dbh.Query(KeysetQuery,Args...); err != nil {
+      t.Errorf(err.Error())
+			return
+		} // END if
+		Doc = v_Doc.TableOpen().TrTdSqlRows("","",Rows).TagCloseTop().PageNav("").String()
+		Rows.Close()
+		if strings.Count(Doc,"<tr") != 3 || !strings.Contains(Doc,fmt.Sprintf(`right">%d</td></tr></table>`,Page*3)) {
+      t.Errorf("KeysetQuery: unexpected page %d of a numeric key »%s«",Page,Doc)
+		} // END if
+		Parameter = map[string]string{"size": "3", GC_ParamAfter: v_Doc.keyNext, GC_ParamAfterType: v_Doc.keyNextType}
+	} // END for
+	if !strings.Contains(Doc,"after=6&amp;after_type=int") {
+    t.Errorf("KeysetQuery: type of the key missing in the link »%s«",Doc)
+	} // END if
+} // END TestTable_Sqlite3Paginate
+
+/* */
+
+// ********************************************
+// Testing footer aggregates of a database query
+// ********************************************
+func TestTable_Sqlite3Aggregate(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	Rows,err := dbh.Query("SELECT Breed, Class, Flying, CAST(Flying*1.5 AS TEXT) AS Score FROM DuckBreeds")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc := New(GC_DocTypeNONE,0x00).Aggregate("Breed","count","Class","count-distinct","Flying","sum","Score","avg:%.3f").
+	         TableOpen().
+	           TrThSqlRows("","",Rows).
+	           TrTdSqlRows("","",Rows).
+	           TfootAggregates("","","").
+	         TagCloseAll().String()
+	Rows.Close()
+	Expected := `<tfoot><tr><td class="" data-aggregate="count">28</td><td class="" data-aggregate="distinct">4</td><td class="" style="text-align: right" data-aggregate="sum">11</td><td class="" data-aggregate="avg">0.589</td></tr></tfoot>`
+	if !strings.Contains(Doc,Expected) {
+    t.Errorf("Aggregate: »%s« not found in »%s«",Expected,Doc)
+	} // END if
+} // END TestTable_Sqlite3Aggregate
+
+/* */
+
+// ********************************************
+// Testing a report of the DuckBreeds grouped by Class
+// ********************************************
+func TestTable_Sqlite3GroupBy(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	Rows,err := dbh.Query("SELECT Class, Breed, Flying FROM DuckBreeds ORDER BY Class, Breed")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc := New(GC_DocTypeNONE,0x00).GroupBy(GC_GroupRowspan,"Total %s","Class").Aggregate("Breed","count","Flying","sum").
+	         TableOpen().
+	           TrThSqlRows("","",Rows).
+	           TbodyOpen().
+	             TrTdSqlRows("","",Rows).
+	           TagCloseTop().
+	           TfootAggregates("","","Total").
+	         TagCloseAll().String()
+	Rows.Close()
+	Expected := []string{
+		`<tr><td class="">Bantam</td><td class="">Mallard</td><td class="" style="text-align: right">2</td></tr><tr class="subtotal"><th class="" scope="row">Total Bantam</th><td class="" data-aggregate="count">1</td><td class="" style="text-align: right" data-aggregate="sum">2</td></tr>`,
+		`<tr><td rowspan="8" class="">Heavy</td><td class="">Aylesbury</td>`,
+		`<td rowspan="10" class="">Light</td>`,
+		`<tr class="subtotal"><th class="" scope="row">Total Medium</th><td class="" data-aggregate="count">9</td><td class="" style="text-align: right" data-aggregate="sum">1</td></tr></tbody>`,
+		`<tfoot><tr><th class="" scope="row">Total</th><td class="" data-aggregate="count">28</td><td class="" style="text-align: right" data-aggregate="sum">11</td></tr></tfoot>`,
+	}
+	for _,Fragment := range Expected {
+		if !strings.Contains(Doc,Fragment) {
+      t.Errorf("GroupBy: »%s« not found in »%s«",Fragment,Doc)
+		} // END if
+	} // END for
+	if strings.Count(Doc,">Heavy<") != 1 {
+    t.Errorf("GroupBy: group value repeated »%s«",Doc)
+	} // END if
+} // END TestTable_Sqlite3GroupBy
+
+/* */
+
+// ********************************************
+// Testing a pivot table of a database query: number of breeds per class and flying ability
+// ********************************************
+func TestTable_Sqlite3Pivot(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	Rows,err := dbh.Query("SELECT Class, Flying FROM DuckBreeds")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc := New(GC_DocTypeNONE,0x00).
+	         TableOpen().
+	           Pivot("","","",T_Pivot{RowField: "Class", ColumnField: "Flying", Empty: "0", RowTotals: true, ColumnTotals: true},Rows).
+	         TagCloseAll().String()
+	Rows.Close()
+	Expected := []string{
+		`<th class="" scope="col">0</th><th class="" scope="col">1</th><th class="" scope="col">2</th><th class="total" scope="col">Total</th>`,
+		`<tr><th class="" scope="row">Light</th><td class="">3</td><td class="">6</td><td class="">1</td><td class="total">10</td></tr>`,
+		`<tfoot><tr><th class="total" scope="row">Total</th><td class="total">19</td><td class="total">7</td><td class="total">2</td><td class="total">28</td></tr></tfoot>`,
+	}
+	for _,Fragment := range Expected {
+		if !strings.Contains(Doc,Fragment) {
+      t.Errorf("Pivot: »%s« not found in »%s«",Fragment,Doc)
+		} // END if
+	} // END for
+	if HTML := New(GC_DocTypeNONE,0x00).Pivot("","","",T_Pivot{RowField: "Class", ColumnField: "Flying"},Rows); HTML.Err() == nil {
+    t.Errorf("Pivot: expected the error of the closed rows »%s«",HTML.String())
+	} // END if
+} // END TestTable_Sqlite3Pivot
+
+/* */
+
+// ********************************************
+// Testing conditional styling: flying breeds are highlighted
+// ********************************************
+func TestTable_Sqlite3StyleRules(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	Rows,err := dbh.Query("SELECT Breed, CASE Flying WHEN 0 THEN 'No' ELSE 'Yes' END AS Flying FROM DuckBreeds ORDER BY Breed")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc := New(GC_DocTypeNONE,0x00).StyleRows(RuleEquals("Flying","Yes","flying")).StyleRowHeader().
+	         TableOpen().
+	           TrThSqlRows("","",Rows).
+	           TrTdSqlRows("","",Rows).
+	         TagCloseAll().String()
+	Rows.Close()
+	if strings.Count(Doc,`<tr class="flying">`) != 9 || !strings.Contains(Doc,`<tr><th class="" scope="row">Aylesbury</th><td class="">No</td></tr>`) {
+    t.Errorf("StyleRules: unexpected rows »%s«",Doc)
+	} // END if
+} // END TestTable_Sqlite3StyleRules
+
+/* */
+
+// ********************************************
+// Testing the values of database/sql: []byte, NULL, time.Time, DECIMAL and errors
+// ********************************************
+func TestTable_Sqlite3Values(t *testing.T) {
+	dbh,err := sql.Open("sqlite",":memory:")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+	if _,err := dbh.Exec(`CREATE TABLE Eggs(Laid DATE, Sold DATETIME, Price DECIMAL(10,2), Note BLOB, Buyer TEXT, Count INTEGER);
+	                      INSERT INTO Eggs VALUES('2024-05-01','2024-05-02 12:30:00',2.5,CAST('brown' AS BLOB),NULL,12)`); err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+
+	Rows,err := dbh.Query("SELECT * FROM Eggs")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	v_Doc := New(GC_DocTypeNONE,0x00).TrTdSqlRows("","",Rows)
+	Rows.Close()
+	Expected := `<tr><td class="">2024-05-01</td><td class="">2024-05-02 12:30:00</td><td class="" style="text-align: right">2.50</td><td class="">brown</td><td class="">&nbsp;</td><td class="" style="text-align: right">12</td></tr>`
+	if v_Doc.String() != Expected || v_Doc.Err() != nil {
+    t.Errorf("Values: expected »%s«, got »%s«, error %v",Expected,v_Doc.String(),v_Doc.Err())
+	} // END if
+
+	// sql.Null* in slices are printed by their value
+	Doc := New(GC_DocTypeNONE,0x00).TrTdSlice("","",[][]any{{sql.NullString{String: "Rouen", Valid: true},sql.NullInt64{},[]byte("Pekin")}}).String()
+	if Doc != `<tr><td class="">Rouen</td><td class="">&nbsp;</td><td class="">Pekin</td></tr>` {
+    t.Errorf("Values: unexpected sql.Null* row »%s«",Doc)
+	} // END if
+
+	// an error in the third row stops the rows and is returned by Err()
+	Rows,err = dbh.Query(`WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM n WHERE x < 5)
+	                      SELECT CASE WHEN x = 3 THEN abs(-9223372036854775808) ELSE x END AS v FROM n`)
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	v_Doc = New(GC_DocTypeNONE,0x00).TrTdSqlRows("","",Rows)
+	Rows.Close()
+	if v_Doc.Err() == nil || strings.Count(v_Doc.String(),"<tr>") != 2 {
+    t.Errorf("Values: expected two rows and an error, got »%s«, error %v",v_Doc.String(),v_Doc.Err())
+	} // END if
+
+	// closed rows: no table rows and the error in Err()
+	Rows,err = dbh.Query("SELECT * FROM Eggs")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Rows.Close()
+	v_Doc = New(GC_DocTypeNONE,0x00).TrThSqlRows("","",Rows).TrTdSqlRows("","",Rows)
+	if v_Doc.Err() == nil || v_Doc.String() != "" {
+    t.Errorf("Values: expected no rows and an error for closed rows, got »%s«, error %v",v_Doc.String(),v_Doc.Err())
+	} // END if
+} // END TestTable_Sqlite3Values
+
+/* */
+
+// ********************************************
+// Testing the one-call table of a query with row limit and errors
+// ********************************************
+func TestTable_Sqlite3QueryTable(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	Layout := T_QueryTable{Caption: "Flying ducks: {rows} of max. {max}", MaxRows: 5, TableClass: "ducks", TdClass: "cell"}
+	v_Doc := New(GC_DocTypeNONE,0x00).QueryTable(context.Background(),dbh,Layout,"SELECT Breed, Flying FROM DuckBreeds WHERE Flying > ? ORDER BY Breed",0)
+	Doc := v_Doc.String()
+	Expected := []string{
+		`<table class="ducks"><caption>Flying ducks: 5 of max. 5</caption><thead><tr><th class="">Breed</th><th class="">Flying</th></tr></thead><tbody>`,
+		`<tr><td class="cell">Hook Bill</td><td class="cell" style="text-align: right">2</td></tr>`,
+		`</tbody><tfoot><tr class="truncated"><td class="cell" colspan="2">Only the first 5 rows are shown.</td></tr></tfoot></table>`,
+	}
+	for _,Fragment := range Expected {
+		if !strings.Contains(Doc,Fragment) {
+      t.Errorf("QueryTable: »%s« not found in »%s«",Fragment,Doc)
+		} // END if
+	} // END for
+	if Result := v_Doc.QueryResult(); Result.Rows != 5 || !Result.Truncated || Result.Err != nil || v_Doc.Err() != nil {
+    t.Errorf("QueryTable: unexpected result %+v",Result)
+	} // END if
+
+	// *sql.Conn, all rows, a failing query
+	Conn,err := dbh.Conn(context.Background())
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer Conn.Close()
+	v_Doc = New(GC_DocTypeNONE,0x00).
+	          QueryTable(context.Background(),Conn,T_QueryTable{Caption: "All ducks"},"SELECT Breed FROM DuckBreeds").
+	          QueryTable(context.Background(),Conn,T_QueryTable{Failed: "Error: {error}"},"SELECT Wings FROM DuckBreeds")
+	Doc = v_Doc.String()
+	if strings.Count(Doc,"<tr><td") != 28 || strings.Contains(Doc,"truncated") || !strings.Contains(Doc,`<caption>All ducks</caption>`) {
+    t.Errorf("QueryTable: unexpected table of all ducks »%s«",Doc)
+	} // END if
+	if !strings.HasSuffix(Doc,`<table><tbody><tr class="error"><td class="" colspan="1">Error: SQL logic error: no such column: Wings (1)</td></tr></tbody></table>`) || v_Doc.Err() == nil || v_Doc.QueryResult().Err == nil {
+    t.Errorf("QueryTable: unexpected error table »%s«",Doc)
+	} // END if
+} // END TestTable_Sqlite3QueryTable
+
+/* */
+
+// ********************************************
+// Testing a select menu from a database query: the breeds grouped by class
+// ********************************************
+
+func TestTable_Sqlite3Select(t *testing.T) {
+	dbh,err := sql.Open("sqlite","Ducks.sqlite3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+
+	Rows,err := dbh.Query("SELECT Breed, Class FROM DuckBreeds WHERE Class IN ('Bantam','Heavy') ORDER BY Class, Breed LIMIT 3")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc := New(GC_DocTypeNONE,0x00).
+	         Select("Breed",T_Select{KeyField: "Breed", GroupField: "Class", Selected: []string{"Aylesbury"}, Placeholder: "Choose a breed"},Rows,"required").
+	         String()
+	Rows.Close()
+	Expected := `<select name="Breed" required><option value="">Choose a breed</option>` +
+	            `<optgroup label="Bantam"><option value="Mallard">Mallard</option></optgroup>` +
+	            `<optgroup label="Heavy"><option value="Aylesbury" selected="selected">Aylesbury</option><option value="Pekin, American White">Pekin, American White</option></optgroup></select>`
+	if Doc != Expected {
+    t.Errorf("Select: expected »%s« got »%s«",Expected,Doc)
+	} // END if
+
+	// errors of the result set are recorded, not panicked
+	for _,Test := range []struct{ Name string; Render func(*T_HTML) *T_HTML }{
+		{"Select", func(p_HTML *T_HTML) *T_HTML { return p_HTML.Select("Breed",T_Select{},Rows) }},
+		{"RadioGroup", func(p_HTML *T_HTML) *T_HTML { return p_HTML.RadioGroup("Breed","","",nil,Rows) }},
+		{"DataList", func(p_HTML *T_HTML) *T_HTML { return p_HTML.DataList("breeds",nil,Rows) }},
+	} {
+		if HTML := Test.Render(New(GC_DocTypeNONE,0x00)); HTML.Err() == nil {
+			t.Errorf("%s: expected an error of the closed rows »%s«",Test.Name,HTML.String())
+		} // END if
+	} // END for
+} // END TestTable_Sqlite3Select
+
+/* */
+
+// ********************************************
+// Testing the tree view of a parent-id adjacency list
+// ********************************************
+func TestTable_Sqlite3Tree(t *testing.T) {
+	dbh,err := sql.Open("sqlite",":memory:")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	defer dbh.Close()
+	if _,err := dbh.Exec(`CREATE TABLE Dirs(Name TEXT, Id INTEGER, Parent INTEGER);
+	                      INSERT INTO Dirs VALUES('/',1,NULL),('usr',2,1),('bin',3,2),('etc',4,1),('lib',5,2),('lost',6,7),('found',7,6)`); err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+
+	Rows,err := dbh.Query("SELECT * FROM Dirs ORDER BY Id")
+	if err != nil {
+    t.Errorf(err.Error())
+		return
+	} // END if
+	Doc := Tree(T_Tree{Open: 1, KeyField: "Id", ParentField: "Parent", LabelField: "Name", Compare: CmpAsc, Label: func(p_Node T_TreeNode) string {
+		if p_Node.Leaf { return "" } // the default label
+		return p_Node.Value.(map[string]any)["Name"].(string) + "/"
+	}},Rows)
+	Rows.Close()
+	Expected := `<ul><li><details open="open"><summary>// <span>2</span></summary><ul><li>etc</li>` +
+	            `<li><details><summary>usr/ <span>2</span></summary><ul><li>bin</li><li>lib</li></ul></details></li></ul></details></li></ul>`
+	if Doc != Expected {
+    t.Errorf("Tree: expected »%s« got »%s«",Expected,Doc)
+	} // END if
+	if HTML := New(GC_DocTypeNONE,0x00).Tree(T_Tree{},Rows); HTML.Err() == nil || HTML.String() != "<ul></ul>" {
+    t.Errorf("Tree: expected an empty tree and the error of the closed rows, got »%s« %v",HTML.String(),HTML.Err())
+	} // END if
+} // END TestTable_Sqlite3Tree
+
+/* */
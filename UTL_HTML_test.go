@@ -8,7 +8,10 @@ import (
   "os"
   "fmt"
   "time"
+  "context"
+  "strings"
   "testing"
+  "net/http/httptest"
 )
 
 // Some little helper functions
@@ -112,3 +115,50 @@ func Test_Table_Static(t *testing.T) {
 } // END Test_Table_Static
 
 /* */
+
+// ***************************************************
+// Testing progressive flushing and early cancellation
+// ***************************************************
+func Test_FlushEvery(t *testing.T) {
+  Recorder := httptest.NewRecorder()
+  v_Doc := New(GC_DocTypeNONE,0x00).
+           FlushEvery(Recorder,1,0).
+           TableOpen().
+             TbodyOpen().
+               TrTdSlice("","",v_CityTable).
+           TagCloseAll()
+  if !Recorder.Flushed {
+    t.Errorf("FlushEvery: nothing has been flushed")
+  } // END if
+  v_Doc.Write(Recorder)
+  Body := Recorder.Body.String()
+  if strings.Count(Body,"<tr") != 3 || strings.Count(Body,"</tr>") != 3 || !strings.HasSuffix(Body,"</tbody></table>") {
+    t.Errorf("FlushEvery: unexpected document »%s«",Body)
+  } // END if
+
+  ctx,cancel := context.WithCancel(context.Background())
+  cancel()
+  v_Doc = New(GC_DocTypeNONE,0x00).WithContext(ctx).TableOpen().TrTdSlice("","",v_CityTable).TagCloseAll()
+  if v_Doc.String() != "<table></table>" {
+    t.Errorf("WithContext: rows generated after cancellation »%s«",v_Doc.String())
+  } // END if
+
+  // a write error stops the rows like a cancelled context
+  Failing := &t_FailingWriter{ResponseRecorder: httptest.NewRecorder()}
+  v_Doc = New(GC_DocTypeNONE,0x00).FlushEvery(Failing,1,0).TableOpen().TrTdSlice("","",v_CityTable).TagCloseAll()
+  if Failing.Writes != 1 || !v_Doc.Cancelled() || v_Doc.Err() == nil || strings.Count(v_Doc.String(),"<tr") != 1 {
+    t.Errorf("FlushEvery: expected one write, no more rows and an error, got %d writes »%s« %v",Failing.Writes,v_Doc.String(),v_Doc.Err())
+  } // END if
+} // END Test_FlushEvery
+
+// A ResponseWriter whose writes fail, like one of a client that is gone
+type t_FailingWriter struct {
+  *httptest.ResponseRecorder
+  Writes int
+}
+func (p_Writer *t_FailingWriter) Write(p_Data []byte) (int, error) {
+  p_Writer.Writes++
+  return 0, fmt.Errorf("broken pipe")
+} // END Write
+
+/* */
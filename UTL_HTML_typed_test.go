@@ -0,0 +1,55 @@
+package UTL_HTML
+// -------------------------------------------------------------------------------------------------
+
+//
+// -------------------------------------------------------------------------------------------------
+
+import (
+  "slices"
+  "strings"
+  "testing"
+)
+
+/* */
+
+// *****************************************
+// Testing typed tables from []T, iter.Seq[T] and map[K]T
+// *****************************************
+var v_CityTable_Typed = NewTable(
+  T_Column[*t_CityRec]{Header: "City", Value: func(c *t_CityRec) any { return c.Name }},
+  T_Column[*t_CityRec]{Header: "Population", Value: func(c *t_CityRec) any { return c.Population }, Align: "right"},
+  T_Column[*t_CityRec]{Header: "Latitude", Value: func(c *t_CityRec) any { return c.Latitude }, Format: "%.2f"},
+  T_Column[*t_CityRec]{Header: "Visited", Render: func(c *t_CityRec) string {
+    if c.Visited { return B("yes") }
+    return "no"
+  }},
+)
+
+func TestTable_Typed(t *testing.T) {
+  Doc := New(GC_DocTypeNONE,0x00).
+         Sortable(map[string]string{"sort": "Population"}).
+         TableOpen().
+           TheadOf(v_CityTable_Typed).
+           TbodyOf(v_CityTable_Typed.Rows(v_CityList)).
+         TagCloseAll().String()
+  if !strings.Contains(Doc,`<td class="">Charleston</td><td class="" style="text-align: right;">46838</td><td class="">38.35</td><td class=""><b>yes</b></td>`) {
+    t.Errorf("Typed: row not found »%s«",Doc)
+  } // END if
+  if Charleston,Houston := strings.Index(Doc,"Charleston"),strings.Index(Doc,"Houston"); Charleston > Houston {
+    t.Errorf("Typed: rows not sorted by population »%s«",Doc)
+  } // END if
+
+  Doc = New(GC_DocTypeNONE,0x00).TbodyOf(v_CityTable_Typed.Seq(slices.Values(v_CityList))).String()
+  if strings.Count(Doc,"<tr") != 3 || !strings.Contains(Doc,"<b>yes</b>") {
+    t.Errorf("Typed: unexpected rows from iter.Seq »%s«",Doc)
+  } // END if
+
+  Table := NewTable(T_Column[string]{Header: "Value", Value: func(s string) any { return s }})
+  Table.KeyHeader = "Name"
+  Doc = New(GC_DocTypeNONE,0x00).TableOpen().TheadOf(Table).TbodyOf(MapRows(Table,Environment)).TagCloseAll().String()
+  if !strings.HasPrefix(Doc,`<table><thead><tr><th class="">Name</th><th class="">Value</th></tr></thead><tbody><tr><td class="">ALLUSERSPROFILE</td>`) {
+    t.Errorf("Typed: unexpected table from map »%s«",Doc)
+  } // END if
+} // END TestTable_Typed
+
+/* */